@@ -0,0 +1,95 @@
+// cmd/polvo-schema/main.go
+//
+// polvo-schema emits the JSON Schema document of every event type.
+//
+// Usage:
+//
+//	polvo-schema [-src dir] [-out dir] [-code eventCode]
+//
+// The examples of the fields are read from the `// example:` comments of the model sources
+// of the polvo-logger module rooted at -src, the current directory by default,
+// so that `go run ./cmd/polvo-schema` works from a checkout. If -src holds no model source,
+// polvo-schema fails instead of omitting the examples. With -src "", examples are omitted.
+// Without -out, the documents are printed to stdout as one JSON object keyed by EventName.
+// With -out, each document is written to <dir>/<EventName>.schema.json.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	jsonSchema "github.com/enki-polvo/polvo-logger/schema"
+)
+
+func main() {
+	srcDir := flag.String("src", ".", "root of the polvo-logger module whose model sources hold the examples, empty to omit them")
+	outDir := flag.String("out", "", "directory to write one <EventName>.schema.json per event type")
+	code := flag.String("code", "", "generate only the event type with this EventCode (name or number)")
+	flag.Parse()
+
+	if err := run(*srcDir, *outDir, *code); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(srcDir, outDir, code string) error {
+	schemas := map[commonModel.EventCode]*jsonSchema.Schema{}
+
+	var examples jsonSchema.Examples
+	if srcDir != "" {
+		var err error
+		if examples, err = jsonSchema.CollectModelExamples(os.DirFS(srcDir)); err != nil {
+			return err
+		}
+	}
+
+	if code != "" {
+		eventCode, err := commonModel.ParseEventCode(code)
+		if err != nil {
+			return err
+		}
+		schema, err := jsonSchema.GenerateWithExamples(eventCode, examples)
+		if err != nil {
+			return err
+		}
+		schemas[eventCode] = schema
+	} else {
+		var err error
+		if schemas, err = jsonSchema.GenerateAllWithExamples(examples); err != nil {
+			return err
+		}
+	}
+
+	if outDir == "" {
+		byName := make(map[string]*jsonSchema.Schema, len(schemas))
+		for eventCode, schema := range schemas {
+			byName[eventCode.String()] = schema
+		}
+		b, err := json.MarshalIndent(byName, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+	for eventCode, schema := range schemas {
+		b, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(outDir, eventCode.String()+".schema.json")
+		if err = os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
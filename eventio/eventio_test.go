@@ -0,0 +1,193 @@
+package eventio_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/enki-polvo/polvo-logger/eventio"
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	eventModel "github.com/enki-polvo/polvo-logger/model/event"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+	eventPool "github.com/enki-polvo/polvo-logger/pool"
+)
+
+// Test writing and reading events with a pool
+// Test that written events are read back in order with their typed metadata
+func TestWriteAndRead(t *testing.T) {
+	pool := eventPool.NewEventPool()
+	var buf bytes.Buffer
+	writer := eventio.NewWriter(&buf)
+
+	for i := int64(1); i <= 3; i++ {
+		event, err := pool.Allocate(commonModel.PROC_TERMINATE)
+		if err != nil {
+			t.Fatalf("Failed to allocate event: %v", err)
+		}
+		event.Source = "eBPF"
+		event.Metadata.(*eventModel.ProcessTerminateMetadata).PID = i
+		if err = writer.Write(event); err != nil {
+			t.Fatalf("Failed to write event: %v", err)
+		}
+		if err = pool.Free(event); err != nil {
+			t.Fatalf("Failed to free event: %v", err)
+		}
+	}
+	if buf.Len() != 0 {
+		t.Fatal("Events should be buffered until Flush")
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Failed to flush: %v", err)
+	}
+
+	reader := eventio.NewReader(&buf, eventio.WithAllocator(pool))
+	for i := int64(1); i <= 3; i++ {
+		event, err := reader.Read()
+		if err != nil {
+			t.Fatalf("Failed to read event: %v", err)
+		}
+		metadata, ok := event.Metadata.(*eventModel.ProcessTerminateMetadata)
+		if !ok || metadata.PID != i {
+			t.Fatalf("Read event does not match: %+v", event.Metadata)
+		}
+		if err = pool.Free(event); err != nil {
+			t.Fatalf("Failed to free event: %v", err)
+		}
+	}
+	if _, err := reader.Read(); !errors.Is(err, io.EOF) {
+		t.Fatalf("Expected io.EOF at the end of the stream, got %v", err)
+	}
+}
+
+// Test reading a stream with malformed lines
+// Test that malformed lines are reported with their line number and reading continues
+func TestReadMalformedLines(t *testing.T) {
+	stream := strings.Join([]string{
+		`{"EventCode":4,"Source":"eBPF","Metadata":{"PID":1,"Op":1}}`,
+		`{"EventCode":4,"Source":`,
+		``,
+		`{"EventCode":999,"Source":"eBPF","Metadata":{}}`,
+		`{"EventCode":4,"Source":"eBPF","Metadata":{"PID":2,"Op":2}}`,
+	}, "\n")
+	reader := eventio.NewReader(strings.NewReader(stream))
+
+	var pids []int64
+	var malformed []int
+	for {
+		event, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var lineErr *eventio.LineError
+		if errors.As(err, &lineErr) {
+			malformed = append(malformed, lineErr.Line)
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		pids = append(pids, event.Metadata.(*eventModel.TcpMetadata).PID)
+	}
+
+	if len(pids) != 2 || pids[0] != 1 || pids[1] != 2 {
+		t.Fatalf("Unexpected events read: %v", pids)
+	}
+	if len(malformed) != 2 || malformed[0] != 2 || malformed[1] != 4 {
+		t.Fatalf("Unexpected malformed lines: %v", malformed)
+	}
+}
+
+// countingPool counts the events given back to its pool.
+type countingPool struct {
+	eventPool.Pool
+	freed int
+}
+
+func (p *countingPool) Free(event eventModel.Event) error {
+	p.freed++
+	return p.Pool.Free(event)
+}
+
+// Test that events failing to decode after allocation are given back to the pool
+func TestReadFreesMalformedEvents(t *testing.T) {
+	stream := strings.Join([]string{
+		`{"EventCode":4,"Source":"eBPF","Metadata":{"PID":"not a number"}}`,
+		`{"EventCode":4,"Source":"eBPF","Metadata":{"PID":2,"Op":2}}`,
+	}, "\n")
+	pool := &countingPool{Pool: eventPool.NewEventPool()}
+	reader := eventio.NewReader(strings.NewReader(stream), eventio.WithAllocator(pool))
+
+	var lineErr *eventio.LineError
+	if _, err := reader.Read(); !errors.As(err, &lineErr) || lineErr.Line != 1 {
+		t.Fatalf("Expected a LineError for line 1, got %v", err)
+	}
+	if pool.freed != 1 {
+		t.Fatalf("Expected the malformed event to be freed, got %d frees", pool.freed)
+	}
+
+	event, err := reader.Read()
+	if err != nil {
+		t.Fatalf("Failed to read event: %v", err)
+	}
+	if metadata, ok := event.Metadata.(*eventModel.TcpMetadata); !ok || metadata.PID != 2 {
+		t.Fatalf("Read event does not match: %+v", event.Metadata)
+	}
+}
+
+// Test that a Writer writes enums as names, and that they are read back
+func TestWriteEnumNames(t *testing.T) {
+	var buf bytes.Buffer
+	writer := eventio.NewWriter(&buf)
+	event := &commonModel.CommonModel{
+		CommonHeader: commonModel.CommonHeader{EventCode: commonModel.TCP_EVENT, EventName: commonModel.TCP_EVENT.String(), Source: "eBPF"},
+		Metadata:     &eventModel.TcpMetadata{PID: 1, Op: state.TCP_CONNECT},
+	}
+	if err := writer.Write(event); err != nil {
+		t.Fatalf("Failed to write event: %v", err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Failed to flush: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"EventCode":"TcpEvent"`) || !strings.HasSuffix(buf.String(), "}\n") {
+		t.Fatalf("Unexpected encoding: %s", buf.String())
+	}
+
+	read, err := eventio.NewReader(&buf).Read()
+	if err != nil {
+		t.Fatalf("Failed to read event: %v", err)
+	}
+	if metadata, ok := read.Metadata.(*eventModel.TcpMetadata); !ok || metadata.Op != state.TCP_CONNECT {
+		t.Fatalf("Read event does not match: %+v", read.Metadata)
+	}
+}
+
+// Test that a Writer with validation rejects invalid events without writing them
+func TestWriteWithValidation(t *testing.T) {
+	var buf bytes.Buffer
+	writer := eventio.NewWriter(&buf, eventio.WithValidation())
+
+	invalid := &commonModel.CommonModel{
+		CommonHeader: commonModel.CommonHeader{EventCode: commonModel.TCP_EVENT, EventName: commonModel.TCP_EVENT.String(), Source: "eBPF"},
+		Metadata:     &eventModel.TcpMetadata{PID: -1, Op: state.TCP_CONNECT},
+	}
+	var fieldErrs commonModel.FieldErrors
+	if err := writer.Write(invalid); !errors.As(err, &fieldErrs) {
+		t.Fatalf("Expected FieldErrors for an invalid event, got %v", err)
+	}
+
+	valid := &commonModel.CommonModel{
+		CommonHeader: commonModel.CommonHeader{EventCode: commonModel.TCP_EVENT, EventName: commonModel.TCP_EVENT.String(), Source: "eBPF"},
+		Metadata:     &eventModel.TcpMetadata{PID: 1, Daddr: "10.0.0.2", Saddr: "10.0.0.1", Dport: 443, Sport: 50000, Protocol: 4, Op: state.TCP_CONNECT},
+	}
+	if err := writer.Write(valid); err != nil {
+		t.Fatalf("Failed to write valid event: %v", err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Failed to flush: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 1 {
+		t.Fatalf("Expected only the valid event to be written, got %d lines: %s", lines, buf.String())
+	}
+}
@@ -0,0 +1,105 @@
+// eventio/reader.go
+package eventio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	// the built-in event types are registered by the eventModel package
+	_ "github.com/enki-polvo/polvo-logger/model/event"
+)
+
+// LineError reports a malformed line of an NDJSON stream.
+// The Reader stays usable after returning a LineError.
+type LineError struct {
+	Line int // 1-based line number
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// ReaderOption configures a Reader created by NewReader.
+type ReaderOption func(*Reader)
+
+// WithAllocator makes the Reader allocate events from allocator, e.g. an eventPool.Pool.
+// The caller owns the returned events and should Free them once done.
+// Events failing to decode are given back to the allocator.
+func WithAllocator(allocator commonModel.Allocator) ReaderOption {
+	return func(r *Reader) {
+		r.allocator = allocator
+	}
+}
+
+// Reader reads events from a newline-delimited JSON stream, one event per line.
+// A Reader is not safe for concurrent use.
+type Reader struct {
+	r         *bufio.Reader
+	allocator commonModel.Allocator
+	line      int
+}
+
+// NewReader returns a Reader reading events from r.
+func NewReader(r io.Reader, opts ...ReaderOption) *Reader {
+	reader := &Reader{r: bufio.NewReader(r)}
+	for _, opt := range opts {
+		opt(reader)
+	}
+	return reader
+}
+
+// Line returns the number of the last line read.
+func (r *Reader) Line() int {
+	return r.line
+}
+
+// Read returns the next event of the stream. Its Metadata holds the structure registered
+// for its EventCode, e.g. *eventModel.TcpMetadata.
+// Blank lines are skipped. A malformed line returns a *LineError, and the next call
+// continues with the following line. Read returns io.EOF at the end of the stream.
+func (r *Reader) Read() (*commonModel.CommonModel, error) {
+	for {
+		data, err := r.r.ReadBytes('\n')
+		if len(data) == 0 && err != nil {
+			return nil, err
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		r.line++
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		event, decodeErr := r.decode(data)
+		if decodeErr != nil {
+			return nil, &LineError{Line: r.line, Err: decodeErr}
+		}
+		return event, nil
+	}
+}
+
+// decode decodes a single line into an event.
+func (r *Reader) decode(data []byte) (*commonModel.CommonModel, error) {
+	if r.allocator != nil {
+		return commonModel.Unmarshal(data, r.allocator)
+	}
+
+	event := &commonModel.CommonModel{}
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
@@ -0,0 +1,64 @@
+// eventio/writer.go
+package eventio
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+)
+
+// WriterOption configures a Writer created by NewWriter or NewWriterSize.
+type WriterOption func(*Writer)
+
+// WithValidation makes the Writer validate every event before writing it.
+// Invalid events are not written, and Write returns their commonModel.FieldErrors.
+func WithValidation() WriterOption {
+	return func(w *Writer) {
+		w.validate = true
+	}
+}
+
+// Writer writes events as newline-delimited JSON, one event per line.
+// Events are buffered until Flush is called or the buffer is full.
+// A Writer is not safe for concurrent use.
+type Writer struct {
+	w        *bufio.Writer
+	encoder  *json.Encoder
+	validate bool
+}
+
+// NewWriter returns a Writer writing events to w with a default buffer size.
+func NewWriter(w io.Writer, opts ...WriterOption) *Writer {
+	return NewWriterSize(w, 0, opts...)
+}
+
+// NewWriterSize returns a Writer writing events to w with a buffer of at least size bytes.
+func NewWriterSize(w io.Writer, size int, opts ...WriterOption) *Writer {
+	buffered := bufio.NewWriterSize(w, size)
+	writer := &Writer{
+		w:       buffered,
+		encoder: json.NewEncoder(buffered),
+	}
+	for _, opt := range opts {
+		opt(writer)
+	}
+	return writer
+}
+
+// Write encodes an event into the buffer.
+// The event is not retained, so an event allocated from eventPool.Pool can be freed right after.
+func (w *Writer) Write(event *commonModel.CommonModel) error {
+	if w.validate {
+		if err := event.Validate(); err != nil {
+			return err
+		}
+	}
+	return w.encoder.Encode(event)
+}
+
+// Flush writes the buffered events to the underlying writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
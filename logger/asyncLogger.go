@@ -0,0 +1,412 @@
+// logger/asyncLogger.go
+
+package logger
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+const (
+	// DEFAULT_QUEUE_SIZE is the default number of messages an AsyncLogger queues.
+	DEFAULT_QUEUE_SIZE = 4096
+	// DEFAULT_BATCH_SIZE is the default number of messages an AsyncLogger writes at once.
+	DEFAULT_BATCH_SIZE = 256
+	// DEFAULT_FLUSH_INTERVAL is the default interval at which an AsyncLogger writes an incomplete batch.
+	DEFAULT_FLUSH_INTERVAL = time.Second
+)
+
+// UNKNOWN_EVENT_CODE counts the dropped messages whose EventName is not an event type.
+const UNKNOWN_EVENT_CODE commonModel.EventCode = -1
+
+// OverflowPolicy defines what an AsyncLogger does with a message when its queue is full.
+type OverflowPolicy int
+
+const (
+	// OVERFLOW_BLOCK waits for room in the queue, or for the context of Log to be done.
+	OVERFLOW_BLOCK OverflowPolicy = iota
+	// OVERFLOW_DROP_NEWEST drops the message being logged.
+	OVERFLOW_DROP_NEWEST
+	// OVERFLOW_DROP_OLDEST drops the oldest queued message to make room.
+	OVERFLOW_DROP_OLDEST
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OVERFLOW_BLOCK:
+		return "BLOCK"
+	case OVERFLOW_DROP_NEWEST:
+		return "DROP_NEWEST"
+	case OVERFLOW_DROP_OLDEST:
+		return "DROP_OLDEST"
+	default:
+		return ""
+	}
+}
+
+var overflowPolicies = []OverflowPolicy{OVERFLOW_BLOCK, OVERFLOW_DROP_NEWEST, OVERFLOW_DROP_OLDEST}
+
+// ParseOverflowPolicy parses an OverflowPolicy from its name (e.g. "DROP_OLDEST") or its number.
+func ParseOverflowPolicy(text string) (OverflowPolicy, error) {
+	return state.ParseEnum(text, overflowPolicies)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p OverflowPolicy) MarshalText() ([]byte, error) {
+	return state.MarshalEnumText(p)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts both names and numbers.
+func (p *OverflowPolicy) UnmarshalText(text []byte) error {
+	value, err := ParseOverflowPolicy(string(text))
+	if err != nil {
+		return err
+	}
+	*p = value
+	return nil
+}
+
+// flushRequest asks the writing goroutine to write the queued messages until ctx is done.
+type flushRequest struct {
+	ctx   context.Context
+	reply chan error
+}
+
+// AsyncOption configures an AsyncLogger created by NewAsyncLogger.
+type AsyncOption func(*AsyncLogger)
+
+// WithQueueSize sets the number of messages the AsyncLogger queues. The default is DEFAULT_QUEUE_SIZE.
+func WithQueueSize(size int) AsyncOption {
+	return func(a *AsyncLogger) {
+		a.queueSize = size
+	}
+}
+
+// WithOverflowPolicy sets what the AsyncLogger does when its queue is full. The default is OVERFLOW_BLOCK.
+func WithOverflowPolicy(policy OverflowPolicy) AsyncOption {
+	return func(a *AsyncLogger) {
+		a.policy = policy
+	}
+}
+
+// WithBatchSize sets the number of messages the AsyncLogger writes at once. The default is DEFAULT_BATCH_SIZE.
+func WithBatchSize(size int) AsyncOption {
+	return func(a *AsyncLogger) {
+		a.batchSize = size
+	}
+}
+
+// WithFlushInterval sets the interval at which the AsyncLogger writes an incomplete batch.
+// The default is DEFAULT_FLUSH_INTERVAL.
+func WithFlushInterval(interval time.Duration) AsyncOption {
+	return func(a *AsyncLogger) {
+		a.flushInterval = interval
+	}
+}
+
+// AsyncLogger queues log messages and writes them to a Logger from a background goroutine,
+// so that logging does not block on the sinks. Messages are written in batches, when a batch
+// is full or at the flush interval. Messages dropped because the queue is full are counted per EventCode.
+// It is safe for concurrent use.
+type AsyncLogger struct {
+	logger        *Logger
+	queueSize     int
+	policy        OverflowPolicy
+	batchSize     int
+	flushInterval time.Duration
+
+	queue   chan *LogMessage
+	flushes chan flushRequest
+	closing chan struct{} // closed when Close starts, releases the blocked Log calls
+	stop    chan struct{}
+	stopped chan struct{}
+
+	closeOnce sync.Once
+	closeMu   sync.RWMutex
+	closed    bool
+	sending   sync.WaitGroup // Log calls past the closed check
+
+	errMu sync.Mutex
+	errs  []error // errors of the background writes since the last Flush
+
+	dropped       atomic.Uint64
+	droppedByCode sync.Map // key: commonModel.EventCode, value: *atomic.Uint64
+}
+
+// NewAsyncLogger returns an AsyncLogger writing to l, and starts its writing goroutine.
+// Close must be called to stop it.
+func NewAsyncLogger(l *Logger, opts ...AsyncOption) *AsyncLogger {
+	a := &AsyncLogger{
+		logger:        l,
+		queueSize:     DEFAULT_QUEUE_SIZE,
+		batchSize:     DEFAULT_BATCH_SIZE,
+		flushInterval: DEFAULT_FLUSH_INTERVAL,
+		flushes:       make(chan flushRequest),
+		closing:       make(chan struct{}),
+		stop:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.queue = make(chan *LogMessage, a.queueSize)
+
+	go a.run()
+	return a
+}
+
+// Log queues the message if it is enabled by the Logger.
+// When the queue is full, the message is handled according to the overflow policy.
+// The message must not be modified after Log. A Log call blocked on a full queue
+// returns ErrLoggerClosed once Close starts.
+func (a *AsyncLogger) Log(ctx context.Context, msg *LogMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	a.closeMu.RLock()
+	if a.closed {
+		a.closeMu.RUnlock()
+		return ErrLoggerClosed
+	}
+	// Close waits for the sending Log calls before draining the queue
+	a.sending.Add(1)
+	a.closeMu.RUnlock()
+	defer a.sending.Done()
+
+	if !a.logger.Enabled(msg) {
+		a.logger.drop(msg)
+		return nil
+	}
+
+	switch a.policy {
+	case OVERFLOW_DROP_NEWEST:
+		select {
+		case a.queue <- msg:
+		default:
+			a.drop(msg)
+		}
+	case OVERFLOW_DROP_OLDEST:
+		for {
+			select {
+			case a.queue <- msg:
+				return nil
+			default:
+			}
+			select {
+			case oldest := <-a.queue:
+				a.drop(oldest)
+			default:
+			}
+		}
+	default:
+		select {
+		case a.queue <- msg:
+		case <-a.closing:
+			return ErrLoggerClosed
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Flush writes every message queued before the call, and returns the errors of the
+// writes since the last Flush. It returns the error of ctx if it is done first;
+// the writing goroutine then stops flushing after its current batch.
+func (a *AsyncLogger) Flush(ctx context.Context) error {
+	reply := make(chan error, 1)
+	select {
+	case a.flushes <- flushRequest{ctx: ctx, reply: reply}:
+	case <-a.stopped:
+		return ErrLoggerClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting messages, drains the queue within the deadline of ctx,
+// stops the writing goroutine and closes the Logger. Messages still queued when ctx
+// is done are counted as dropped. If the writing goroutine is still writing a batch
+// when ctx is done, Close returns the error of ctx without waiting for it, and the
+// Logger is closed once the batch is written.
+func (a *AsyncLogger) Close(ctx context.Context) error {
+	// release the Log calls blocked on a full queue before waiting for them
+	a.closeOnce.Do(func() { close(a.closing) })
+
+	a.closeMu.Lock()
+	if a.closed {
+		a.closeMu.Unlock()
+		return nil
+	}
+	a.closed = true
+	a.closeMu.Unlock()
+	a.sending.Wait()
+
+	err := a.Flush(ctx)
+	close(a.stop)
+
+	select {
+	case <-a.stopped:
+		// no message is queued anymore, and the writing goroutine takes no more messages
+		a.dropQueued()
+		return errors.Join(err, a.logger.Close())
+	case <-ctx.Done():
+	}
+
+	// the writing goroutine stops after its current batch, and drops the messages it holds
+	a.dropQueued()
+	go func() {
+		<-a.stopped
+		a.logger.Close()
+	}()
+	if err == nil {
+		err = ctx.Err()
+	}
+	return err
+}
+
+// dropQueued counts the messages left in the queue as dropped and removes them.
+// It may run along the writing goroutine, since each message is received by only one of them.
+func (a *AsyncLogger) dropQueued() {
+	for {
+		select {
+		case msg := <-a.queue:
+			a.drop(msg)
+		default:
+			return
+		}
+	}
+}
+
+// run writes the queued messages in batches until the AsyncLogger is stopped.
+func (a *AsyncLogger) run() {
+	defer close(a.stopped)
+
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*LogMessage, 0, a.batchSize)
+	defer func() {
+		// the messages of an incomplete batch were not written
+		for _, msg := range batch {
+			a.drop(msg)
+		}
+	}()
+
+	for {
+		// stop before taking more messages, even if some are queued
+		select {
+		case <-a.stop:
+			return
+		default:
+		}
+
+		select {
+		case msg := <-a.queue:
+			batch = append(batch, msg)
+			if len(batch) >= a.batchSize {
+				batch = a.write(context.Background(), batch)
+			}
+		case <-ticker.C:
+			batch = a.write(context.Background(), batch)
+		case req := <-a.flushes:
+			batch = a.flush(req.ctx, batch)
+			req.reply <- a.takeErrors()
+		case <-a.stop:
+			return
+		}
+	}
+}
+
+// flush writes the batch and everything queued before the flush request, until ctx is done
+// or the AsyncLogger is stopped. It returns the batch, which is not empty if flush gave up.
+func (a *AsyncLogger) flush(ctx context.Context, batch []*LogMessage) []*LogMessage {
+	for {
+		if ctx.Err() != nil {
+			return batch
+		}
+		select {
+		case <-a.stop:
+			return batch
+		default:
+		}
+
+		if len(batch) >= a.batchSize || (len(a.queue) == 0 && len(batch) > 0) {
+			batch = a.write(ctx, batch)
+			continue
+		}
+		select {
+		case msg := <-a.queue:
+			batch = append(batch, msg)
+		default:
+			// the queue and the batch are empty
+			return batch
+		}
+	}
+}
+
+// write writes a batch to the Logger, records its error, and returns the emptied batch.
+func (a *AsyncLogger) write(ctx context.Context, batch []*LogMessage) []*LogMessage {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := a.logger.LogBatch(ctx, batch); err != nil {
+		a.errMu.Lock()
+		a.errs = append(a.errs, err)
+		a.errMu.Unlock()
+	}
+	clear(batch)
+	return batch[:0]
+}
+
+// takeErrors returns the joined errors of the background writes and forgets them.
+func (a *AsyncLogger) takeErrors() error {
+	a.errMu.Lock()
+	defer a.errMu.Unlock()
+
+	err := errors.Join(a.errs...)
+	a.errs = nil
+	return err
+}
+
+// drop counts a message dropped from the queue under the EventCode of its EventName.
+func (a *AsyncLogger) drop(msg *LogMessage) {
+	code, err := commonModel.ParseEventCode(msg.EventName)
+	if err != nil {
+		code = UNKNOWN_EVENT_CODE
+	}
+	a.dropped.Add(1)
+	counter, _ := a.droppedByCode.LoadOrStore(code, new(atomic.Uint64))
+	counter.(*atomic.Uint64).Add(1)
+}
+
+// Dropped returns the number of messages dropped because the queue was full or not drained on Close.
+// Messages below the minimum level are counted by the Logger.
+func (a *AsyncLogger) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// DroppedByEventCode returns the number of messages dropped from the queue per EventCode.
+// Messages whose EventName is not an event type are counted under UNKNOWN_EVENT_CODE.
+func (a *AsyncLogger) DroppedByEventCode() map[commonModel.EventCode]uint64 {
+	dropped := map[commonModel.EventCode]uint64{}
+	a.droppedByCode.Range(func(key, value any) bool {
+		dropped[key.(commonModel.EventCode)] = value.(*atomic.Uint64).Load()
+		return true
+	})
+	return dropped
+}
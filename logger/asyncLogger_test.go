@@ -0,0 +1,376 @@
+package logger_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enki-polvo/polvo-logger/logger"
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+)
+
+// gatedSink records the written messages, and blocks every write until its gate is opened.
+type gatedSink struct {
+	gate chan struct{}
+
+	mu      sync.Mutex
+	written []*logger.LogMessage
+	batches int
+}
+
+func newGatedSink() *gatedSink {
+	return &gatedSink{gate: make(chan struct{})}
+}
+
+func (s *gatedSink) Write(ctx context.Context, msg *logger.LogMessage) error {
+	return s.WriteBatch(ctx, []*logger.LogMessage{msg})
+}
+
+func (s *gatedSink) WriteBatch(ctx context.Context, msgs []*logger.LogMessage) error {
+	<-s.gate
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.written = append(s.written, msgs...)
+	s.batches++
+	return nil
+}
+
+func (s *gatedSink) Close() error { return nil }
+
+func (s *gatedSink) Written() []*logger.LogMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*logger.LogMessage(nil), s.written...)
+}
+
+// buildAsyncLog builds a message whose Log is the given text.
+func buildAsyncLog(t *testing.T, eventName, text string) *logger.LogMessage {
+	t.Helper()
+	msg, err := logger.BuildLog("eBPF", eventName, text, "", map[string]any{})
+	if err != nil {
+		t.Fatalf("Failed to build log: %v", err)
+	}
+	return msg
+}
+
+// fillQueue logs a first message, waits for the writer to block on it, then fills the queue.
+func fillQueue(t *testing.T, async *logger.AsyncLogger, queueSize int) {
+	t.Helper()
+	if err := async.Log(context.Background(), buildAsyncLog(t, "ProcessCreate", "in flight")); err != nil {
+		t.Fatalf("Failed to log message: %v", err)
+	}
+	// the writer takes the first message out of the queue at the flush interval
+	time.Sleep(50 * time.Millisecond)
+	for i := 0; i < queueSize; i++ {
+		if err := async.Log(context.Background(), buildAsyncLog(t, "ProcessCreate", "queued")); err != nil {
+			t.Fatalf("Failed to log message: %v", err)
+		}
+	}
+}
+
+// Test that overflow policies are encoded as text like the other enums and decoded from their names
+func TestOverflowPolicyText(t *testing.T) {
+	text, err := logger.OVERFLOW_DROP_OLDEST.MarshalText()
+	if err != nil {
+		t.Fatalf("Failed to marshal overflow policy: %v", err)
+	}
+	if string(text) != "DROP_OLDEST" {
+		t.Fatalf("Unexpected overflow policy text: %s", text)
+	}
+	var config struct {
+		Policy logger.OverflowPolicy `json:"Policy"`
+	}
+	if err = json.Unmarshal([]byte(`{"Policy":"DROP_NEWEST"}`), &config); err != nil || config.Policy != logger.OVERFLOW_DROP_NEWEST {
+		t.Fatalf("Failed to unmarshal overflow policy: %v, %v", config.Policy, err)
+	}
+	if _, err = logger.ParseOverflowPolicy("DROP_ALL"); err == nil {
+		t.Fatal("Expected an error due to unknown overflow policy, but got none")
+	}
+}
+
+// Test that the newest messages are dropped and counted per EventCode when the queue is full
+func TestAsyncLoggerDropNewest(t *testing.T) {
+	sink := newGatedSink()
+	async := logger.NewAsyncLogger(logger.NewLogger(logger.WithSink(sink)),
+		logger.WithQueueSize(2),
+		logger.WithBatchSize(1),
+		logger.WithFlushInterval(10*time.Millisecond),
+		logger.WithOverflowPolicy(logger.OVERFLOW_DROP_NEWEST))
+	fillQueue(t, async, 2)
+
+	for _, eventName := range []string{"ProcessCreate", "TcpEvent", "TcpEvent", "CustomEvent"} {
+		if err := async.Log(context.Background(), buildAsyncLog(t, eventName, "dropped")); err != nil {
+			t.Fatalf("Failed to log message: %v", err)
+		}
+	}
+	if async.Dropped() != 4 {
+		t.Fatalf("Expected 4 dropped messages, got %d", async.Dropped())
+	}
+	dropped := async.DroppedByEventCode()
+	if dropped[commonModel.PROC_CREATE] != 1 || dropped[commonModel.TCP_EVENT] != 2 || dropped[logger.UNKNOWN_EVENT_CODE] != 1 {
+		t.Fatalf("Dropped messages per EventCode do not match: %v", dropped)
+	}
+
+	close(sink.gate)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := async.Close(ctx); err != nil {
+		t.Fatalf("Failed to close async logger: %v", err)
+	}
+	for _, msg := range sink.Written() {
+		if msg.Log == "dropped" {
+			t.Fatalf("A dropped message was written: %+v", msg)
+		}
+	}
+	if len(sink.Written()) != 3 {
+		t.Fatalf("Expected 3 written messages, got %d", len(sink.Written()))
+	}
+}
+
+// Test that the oldest queued messages make room for the new ones when the queue is full
+func TestAsyncLoggerDropOldest(t *testing.T) {
+	sink := newGatedSink()
+	async := logger.NewAsyncLogger(logger.NewLogger(logger.WithSink(sink)),
+		logger.WithQueueSize(2),
+		logger.WithBatchSize(1),
+		logger.WithFlushInterval(10*time.Millisecond),
+		logger.WithOverflowPolicy(logger.OVERFLOW_DROP_OLDEST))
+	fillQueue(t, async, 2)
+
+	for _, text := range []string{"newest 1", "newest 2"} {
+		if err := async.Log(context.Background(), buildAsyncLog(t, "TcpEvent", text)); err != nil {
+			t.Fatalf("Failed to log message: %v", err)
+		}
+	}
+	// the dropped messages are the queued ProcessCreate ones
+	if dropped := async.DroppedByEventCode(); dropped[commonModel.PROC_CREATE] != 2 || async.Dropped() != 2 {
+		t.Fatalf("Dropped messages per EventCode do not match: %v", dropped)
+	}
+
+	close(sink.gate)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := async.Close(ctx); err != nil {
+		t.Fatalf("Failed to close async logger: %v", err)
+	}
+	written := sink.Written()
+	if len(written) != 3 || written[0].Log != "in flight" || written[1].Log != "newest 1" || written[2].Log != "newest 2" {
+		t.Fatalf("Written messages do not match: %d messages", len(written))
+	}
+}
+
+// Test that the blocking policy waits for room in the queue until the context is done
+func TestAsyncLoggerBlock(t *testing.T) {
+	sink := newGatedSink()
+	async := logger.NewAsyncLogger(logger.NewLogger(logger.WithSink(sink)),
+		logger.WithQueueSize(1),
+		logger.WithBatchSize(1),
+		logger.WithFlushInterval(10*time.Millisecond))
+	fillQueue(t, async, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := async.Log(ctx, buildAsyncLog(t, "ProcessCreate", "blocked")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected DeadlineExceeded while the queue is full, got %v", err)
+	}
+
+	close(sink.gate)
+	if err := async.Log(context.Background(), buildAsyncLog(t, "ProcessCreate", "unblocked")); err != nil {
+		t.Fatalf("Failed to log message: %v", err)
+	}
+	closeCtx, closeCancel := context.WithTimeout(context.Background(), time.Second)
+	defer closeCancel()
+	if err := async.Close(closeCtx); err != nil {
+		t.Fatalf("Failed to close async logger: %v", err)
+	}
+	if len(sink.Written()) != 3 || async.Dropped() != 0 {
+		t.Fatalf("Expected 3 written and no dropped messages, got %d and %d", len(sink.Written()), async.Dropped())
+	}
+}
+
+// Test that Close returns while a Log call is blocked on a full queue and the sink never returns
+func TestAsyncLoggerCloseBlockedLog(t *testing.T) {
+	sink := newGatedSink()
+	defer close(sink.gate)
+	async := logger.NewAsyncLogger(logger.NewLogger(logger.WithSink(sink)),
+		logger.WithQueueSize(1),
+		logger.WithBatchSize(1),
+		logger.WithFlushInterval(10*time.Millisecond))
+	fillQueue(t, async, 1)
+
+	logged := make(chan error, 1)
+	go func() {
+		logged <- async.Log(context.Background(), buildAsyncLog(t, "ProcessCreate", "blocked"))
+	}()
+	// let the Log call block on the full queue
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	closed := make(chan error, 1)
+	go func() { closed <- async.Close(ctx) }()
+
+	select {
+	case err := <-closed:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected DeadlineExceeded from Close, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close blocked on the blocked Log call")
+	}
+	if err := <-logged; !errors.Is(err, logger.ErrLoggerClosed) {
+		t.Fatalf("Expected ErrLoggerClosed from the blocked Log call, got %v", err)
+	}
+}
+
+// Test that Flush writes the queued messages in batches, and that the messages below the minimum level are not queued
+func TestAsyncLoggerFlush(t *testing.T) {
+	sink := newGatedSink()
+	close(sink.gate)
+	l := logger.NewLogger(logger.WithSink(sink), logger.WithMinLevel(logger.LEVEL_INFO))
+	async := logger.NewAsyncLogger(l, logger.WithBatchSize(4), logger.WithFlushInterval(time.Hour))
+
+	for i := 0; i < 10; i++ {
+		if err := async.Log(context.Background(), buildAsyncLog(t, "ProcessCreate", "flushed")); err != nil {
+			t.Fatalf("Failed to log message: %v", err)
+		}
+	}
+	if err := async.Log(context.Background(), buildAsyncLog(t, "FileOpenEvent", "debug")); err != nil {
+		t.Fatalf("Failed to log message: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := async.Flush(ctx); err != nil {
+		t.Fatalf("Failed to flush async logger: %v", err)
+	}
+	if len(sink.Written()) != 10 {
+		t.Fatalf("Expected 10 written messages after Flush, got %d", len(sink.Written()))
+	}
+	sink.mu.Lock()
+	batches := sink.batches
+	sink.mu.Unlock()
+	if batches != 3 {
+		t.Fatalf("Expected 3 batches of at most 4 messages, got %d", batches)
+	}
+	if l.Dropped() != 1 || async.Dropped() != 0 {
+		t.Fatalf("Expected the debug message to be dropped by the logger, got %d and %d", l.Dropped(), async.Dropped())
+	}
+
+	if err := async.Close(ctx); err != nil {
+		t.Fatalf("Failed to close async logger: %v", err)
+	}
+	if err := async.Log(ctx, buildAsyncLog(t, "ProcessCreate", "closed")); !errors.Is(err, logger.ErrLoggerClosed) {
+		t.Fatalf("Expected ErrLoggerClosed after Close, got %v", err)
+	}
+}
+
+// Test that Close gives up at the deadline and counts the messages left in the queue as dropped
+func TestAsyncLoggerCloseDeadline(t *testing.T) {
+	sink := newGatedSink()
+	async := logger.NewAsyncLogger(logger.NewLogger(logger.WithSink(sink)),
+		logger.WithQueueSize(4),
+		logger.WithBatchSize(1),
+		logger.WithFlushInterval(10*time.Millisecond))
+	fillQueue(t, async, 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	// the writer is released after the deadline, so that it can stop
+	released := make(chan struct{})
+	time.AfterFunc(100*time.Millisecond, func() { close(sink.gate); close(released) })
+	if err := async.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected DeadlineExceeded from Close, got %v", err)
+	}
+	select {
+	case <-released:
+		t.Fatal("Close waited for the blocked writer")
+	default:
+	}
+	if async.Dropped() != 2 {
+		t.Fatalf("Expected the 2 queued messages to be dropped, got %d", async.Dropped())
+	}
+	<-released
+}
+
+// slowSink records the written messages, taking delay per batch, and records when it is closed.
+type slowSink struct {
+	delay time.Duration
+
+	mu      sync.Mutex
+	written int
+	closed  bool
+}
+
+func (s *slowSink) Write(ctx context.Context, msg *logger.LogMessage) error {
+	return s.WriteBatch(ctx, []*logger.LogMessage{msg})
+}
+
+func (s *slowSink) WriteBatch(ctx context.Context, msgs []*logger.LogMessage) error {
+	time.Sleep(s.delay)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.written += len(msgs)
+	return nil
+}
+
+func (s *slowSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *slowSink) state() (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.written, s.closed
+}
+
+// Test that Close returns at the deadline while a slow sink is draining the queue
+// Test that every message is either written or counted as dropped
+func TestAsyncLoggerCloseSlowSink(t *testing.T) {
+	const messages = 20
+	sink := &slowSink{delay: 20 * time.Millisecond}
+	async := logger.NewAsyncLogger(logger.NewLogger(logger.WithSink(sink)),
+		logger.WithQueueSize(messages),
+		logger.WithBatchSize(1),
+		logger.WithFlushInterval(time.Hour))
+	for i := 0; i < messages; i++ {
+		if err := async.Log(context.Background(), buildAsyncLog(t, "TcpEvent", "queued")); err != nil {
+			t.Fatalf("Failed to log message: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := async.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected DeadlineExceeded from Close, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Fatalf("Close did not honour the deadline: took %v", elapsed)
+	}
+	if async.Dropped() == 0 {
+		t.Fatal("Expected the messages left in the queue to be dropped")
+	}
+
+	// the Logger is closed once the batch being written is done
+	deadline := time.Now().Add(time.Second)
+	for {
+		written, closed := sink.state()
+		if closed {
+			if uint64(written)+async.Dropped() != messages {
+				t.Fatalf("Messages were lost: %d written, %d dropped", written, async.Dropped())
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Logger was not closed after the writer stopped")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,125 @@
+// logger/level.go
+
+package logger
+
+import (
+	"sync"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+// Level defines the severity of a log message.
+type Level int
+
+const (
+	// LEVEL_UNSET resolves to the default level of the message's EventName, see ResolveLevel.
+	LEVEL_UNSET Level = iota
+	LEVEL_DEBUG
+	LEVEL_INFO
+	LEVEL_WARNING
+	LEVEL_ERROR
+	LEVEL_CRITICAL
+)
+
+func (l Level) String() string {
+	switch l {
+	case LEVEL_UNSET:
+		return "UNSET"
+	case LEVEL_DEBUG:
+		return "DEBUG"
+	case LEVEL_INFO:
+		return "INFO"
+	case LEVEL_WARNING:
+		return "WARNING"
+	case LEVEL_ERROR:
+		return "ERROR"
+	case LEVEL_CRITICAL:
+		return "CRITICAL"
+	default:
+		return ""
+	}
+}
+
+var levels = []Level{LEVEL_UNSET, LEVEL_DEBUG, LEVEL_INFO, LEVEL_WARNING, LEVEL_ERROR, LEVEL_CRITICAL}
+
+// ParseLevel parses a Level from its name (e.g. "WARNING") or its number.
+func ParseLevel(text string) (Level, error) {
+	return state.ParseEnum(text, levels)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (l Level) MarshalText() ([]byte, error) {
+	return state.MarshalEnumText(l)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts both names and numbers.
+func (l *Level) UnmarshalText(text []byte) error {
+	value, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = value
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (l Level) MarshalJSON() ([]byte, error) {
+	return state.MarshalEnumJSON(l)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both strings and numbers.
+func (l *Level) UnmarshalJSON(data []byte) error {
+	return state.UnmarshalEnumJSON(data, l)
+}
+
+// DEFAULT_LEVEL is the level of messages whose EventName has no default level.
+const DEFAULT_LEVEL = LEVEL_INFO
+
+var (
+	defaultLevelsMu sync.RWMutex
+	// defaultLevels maps the built-in event types to their default level.
+	// File opens are frequent and mostly benign, so they are debug messages.
+	defaultLevels = map[commonModel.EventCode]Level{
+		commonModel.PROC_CREATE:        LEVEL_INFO,
+		commonModel.PROC_TERMINATE:     LEVEL_INFO,
+		commonModel.PROC_BASH_READLINE: LEVEL_INFO,
+		commonModel.PROC_SERVICE:       LEVEL_INFO,
+		commonModel.TCP_EVENT:          LEVEL_INFO,
+		commonModel.FILE_OPEN_EVENT:    LEVEL_DEBUG,
+		commonModel.FILE_RENAME_EVENT:  LEVEL_INFO,
+	}
+)
+
+// SetDefaultLevel sets the default level of the messages of an event type,
+// e.g. of a registered third-party event type.
+func SetDefaultLevel(code commonModel.EventCode, level Level) {
+	defaultLevelsMu.Lock()
+	defer defaultLevelsMu.Unlock()
+
+	defaultLevels[code] = level
+}
+
+// DefaultLevel returns the default level of the messages of an event type, or DEFAULT_LEVEL.
+func DefaultLevel(code commonModel.EventCode) Level {
+	defaultLevelsMu.RLock()
+	defer defaultLevelsMu.RUnlock()
+
+	if level, isExists := defaultLevels[code]; isExists {
+		return level
+	}
+	return DEFAULT_LEVEL
+}
+
+// ResolveLevel returns the level of the message: its Level if set, otherwise the default level
+// of the event type named by its EventName (e.g. "FileOpenEvent"), otherwise DEFAULT_LEVEL.
+func (m *LogMessage) ResolveLevel() Level {
+	if m.Level != LEVEL_UNSET {
+		return m.Level
+	}
+	code, err := commonModel.ParseEventCode(m.EventName)
+	if err != nil {
+		return DEFAULT_LEVEL
+	}
+	return DefaultLevel(code)
+}
@@ -0,0 +1,77 @@
+package logger_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/enki-polvo/polvo-logger/logger"
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+)
+
+// Test the level of messages without an explicit Level
+func TestResolveLevel(t *testing.T) {
+	tests := []struct {
+		msg  logger.LogMessage
+		want logger.Level
+	}{
+		{msg: logger.LogMessage{EventName: commonModel.FILE_OPEN_EVENT.String()}, want: logger.LEVEL_DEBUG},
+		{msg: logger.LogMessage{EventName: commonModel.PROC_CREATE.String()}, want: logger.LEVEL_INFO},
+		{msg: logger.LogMessage{EventName: "openat"}, want: logger.DEFAULT_LEVEL},
+		{msg: logger.LogMessage{EventName: commonModel.FILE_OPEN_EVENT.String(), Level: logger.LEVEL_ERROR}, want: logger.LEVEL_ERROR},
+	}
+	for _, test := range tests {
+		if got := test.msg.ResolveLevel(); got != test.want {
+			t.Fatalf("Resolved level of %v does not match: got %v, want %v", test.msg.EventName, got, test.want)
+		}
+	}
+}
+
+// Test the filtering and the drop counters of a Logger
+func TestLoggerFiltering(t *testing.T) {
+	l := logger.NewLogger(
+		logger.WithMinLevel(logger.LEVEL_WARNING),
+		logger.WithEventLevel("TcpEvent", logger.LEVEL_DEBUG),
+	)
+
+	tests := []struct {
+		msg  logger.LogMessage
+		want bool
+	}{
+		{msg: logger.LogMessage{EventName: "ProcessCreate"}, want: false},
+		{msg: logger.LogMessage{EventName: "ProcessCreate", Level: logger.LEVEL_CRITICAL}, want: true},
+		{msg: logger.LogMessage{EventName: "TcpEvent"}, want: true},
+		{msg: logger.LogMessage{EventName: "FileOpenEvent"}, want: false},
+	}
+	for _, test := range tests {
+		if got := l.Enabled(&test.msg); got != test.want {
+			t.Fatalf("Enabled of %+v does not match: got %v, want %v", test.msg, got, test.want)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := l.Log(context.Background(), &logger.LogMessage{EventName: "FileOpenEvent", Log: "dropped"}); err != nil {
+			t.Fatalf("Failed to log message: %v", err)
+		}
+	}
+	if l.Dropped() != 3 || l.DroppedByEventName()["FileOpenEvent"] != 3 {
+		t.Fatalf("Drop counters do not match: %d, %v", l.Dropped(), l.DroppedByEventName())
+	}
+}
+
+// Test that the level is written only when set
+func TestLogMessageLevelJSON(t *testing.T) {
+	msg, err := logger.BuildLog("eBPF", "openat", "opened", "", nil)
+	if err != nil {
+		t.Fatalf("Failed to build log: %v", err)
+	}
+
+	decoded := map[string]any{}
+	b, _ := json.Marshal(msg)
+	if err = json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	if _, isExists := decoded["level"]; isExists {
+		t.Fatalf("Unset level is written: %s", b)
+	}
+}
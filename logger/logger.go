@@ -3,24 +3,45 @@
 package logger
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"time"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
 )
 
 // LogMessage defines the unified log message structure.
 type LogMessage struct {
-	EventName string         `json:"eventname"`
-	Source    string         `json:"source"`
-	Timestamp string         `json:"timestamp"`
-	Log       string         `json:"log"`
-	Metadata  map[string]any `json:"metadata"`
+	EventName    string         `json:"eventname"`
+	Source       string         `json:"source"`
+	Timestamp    string         `json:"timestamp"`
+	Log          string         `json:"log"`
+	Metadata     map[string]any `json:"metadata"`
+	Level        Level          `json:"level,omitempty"` // LEVEL_UNSET resolves to the default level of EventName
+	HostID       string         `json:"hostid,omitempty"`
+	Hostname     string         `json:"hostname,omitempty"`
+	BootID       string         `json:"bootid,omitempty"`
+	AgentVersion string         `json:"agentversion,omitempty"`
+}
+
+// StampHostIdentity copies the host identity into the log message.
+func (m *LogMessage) StampHostIdentity(identity commonModel.HostIdentity) {
+	m.HostID = identity.HostID
+	m.Hostname = identity.Hostname
+	m.BootID = identity.BootID
+	m.AgentVersion = identity.AgentVersion
+}
+
+// hasHostIdentity reports whether any field of the host identity is set.
+func (m *LogMessage) hasHostIdentity() bool {
+	return m.HostID != "" || m.Hostname != "" || m.BootID != "" || m.AgentVersion != ""
 }
 
 // BuildLog constructs the log message using a structured type.
 // The 'timestamp' parameter is optional. If it's an empty string, the current time is used.
 // Otherwise, it will be accepted if it matches one of the allowed layouts.
+// The message has no host identity; see Logger.BuildLog.
 func BuildLog(source, eventName, eventLog, timestampStr string, metadata map[string]any) (*LogMessage, error) {
 	// Validate required fields.
 	if source == "" {
@@ -58,41 +79,53 @@ func BuildLog(source, eventName, eventLog, timestampStr string, metadata map[str
 		}
 	}
 
-	return &LogMessage{
+	logMsg := &LogMessage{
 		EventName: eventName,
 		Source:    source,
 		Timestamp: timestampStr,
 		Log:       eventLog,
 		Metadata:  metadata,
-	}, nil
+	}
+	return logMsg, nil
 }
 
-// PrintLog prints the unified log message as a one-line JSON string.
-func PrintLog(source, eventName, eventLog, timestamp string, metadata map[string]any) {
-	logMsg, err := BuildLog(source, eventName, eventLog, timestamp, metadata)
-	if err != nil {
-		fmt.Println("Error:", err)
-		return
-	}
-	b, err := json.Marshal(logMsg)
+// BuildLog constructs the log message like the BuildLog function, and stamps the host
+// identity of the Logger on it, if the Logger has one.
+func (l *Logger) BuildLog(source, eventName, eventLog, timestampStr string, metadata map[string]any) (*LogMessage, error) {
+	logMsg, err := BuildLog(source, eventName, eventLog, timestampStr, metadata)
 	if err != nil {
-		fmt.Println("Error marshalling JSON:", err)
-		return
+		return nil, err
 	}
-	fmt.Println(string(b))
+	l.stampHostIdentity(logMsg)
+	return logMsg, nil
+}
+
+var (
+	// defaultLogger and prettyLogger back PrintLog and PrintLogPretty, which print every message.
+	defaultLogger = NewLogger(WithMinLevel(LEVEL_DEBUG))
+	prettyLogger  = NewLogger(WithMinLevel(LEVEL_DEBUG), WithSink(NewStdoutSink(WithPrettyJSON())))
+)
+
+// PrintLog prints the unified log message as a one-line JSON string.
+// Errors are printed as well; use a Logger to get them returned.
+func PrintLog(source, eventName, eventLog, timestamp string, metadata map[string]any) {
+	printLog(defaultLogger, source, eventName, eventLog, timestamp, metadata)
 }
 
 // PrintLogPretty prints the unified log message as a pretty-printed JSON.
+// Errors are printed as well; use a Logger to get them returned.
 func PrintLogPretty(source, eventName, eventLog, timestamp string, metadata map[string]any) {
-	logMsg, err := BuildLog(source, eventName, eventLog, timestamp, metadata)
+	printLog(prettyLogger, source, eventName, eventLog, timestamp, metadata)
+}
+
+// printLog builds the log message and writes it with l, printing errors.
+func printLog(l *Logger, source, eventName, eventLog, timestamp string, metadata map[string]any) {
+	logMsg, err := l.BuildLog(source, eventName, eventLog, timestamp, metadata)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
 	}
-	b, err := json.MarshalIndent(logMsg, "", "  ")
-	if err != nil {
-		fmt.Println("Error marshalling JSON:", err)
-		return
+	if err = l.Log(context.Background(), logMsg); err != nil {
+		fmt.Println("Error:", err)
 	}
-	fmt.Println(string(b))
 }
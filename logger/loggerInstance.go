@@ -0,0 +1,204 @@
+// logger/loggerInstance.go
+
+package logger
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+)
+
+var (
+	ErrLoggerClosed = errors.New("logger is closed")
+)
+
+// Option configures a Logger created by NewLogger.
+type Option func(*Logger)
+
+// WithSink adds sinks the Logger writes every enabled message to.
+// Without sinks, the Logger writes to the standard output.
+func WithSink(sinks ...Sink) Option {
+	return func(l *Logger) {
+		l.sinks = append(l.sinks, sinks...)
+	}
+}
+
+// WithMinLevel sets the minimum level of the messages the Logger writes. The default is DEFAULT_LEVEL.
+func WithMinLevel(level Level) Option {
+	return func(l *Logger) {
+		l.minLevel = level
+	}
+}
+
+// WithEventLevel overrides the minimum level for the messages with the given EventName.
+func WithEventLevel(eventName string, level Level) Option {
+	return func(l *Logger) {
+		l.eventLevels[eventName] = level
+	}
+}
+
+// WithHostIdentity makes the Logger stamp the host identity on the messages it builds or writes
+// that have none. See commonModel.LoadHostIdentity.
+func WithHostIdentity(identity commonModel.HostIdentity) Option {
+	return func(l *Logger) {
+		l.identity = &identity
+	}
+}
+
+// Logger writes the log messages whose level reaches its minimum level to its sinks.
+// Messages below the minimum level are dropped and counted.
+// It is safe for concurrent use.
+type Logger struct {
+	sinks       []Sink
+	minLevel    Level
+	eventLevels map[string]Level
+	identity    *commonModel.HostIdentity // optional, see WithHostIdentity
+
+	closeMu sync.RWMutex
+	closed  bool
+
+	dropped        atomic.Uint64
+	droppedByEvent sync.Map // key: EventName, value: *atomic.Uint64
+}
+
+// NewLogger returns a Logger configured by opts.
+func NewLogger(opts ...Option) *Logger {
+	l := &Logger{
+		minLevel:    DEFAULT_LEVEL,
+		eventLevels: map[string]Level{},
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if len(l.sinks) == 0 {
+		l.sinks = []Sink{NewStdoutSink()}
+	}
+	return l
+}
+
+// Enabled reports whether the message reaches the minimum level of its EventName.
+func (l *Logger) Enabled(msg *LogMessage) bool {
+	minLevel, isExists := l.eventLevels[msg.EventName]
+	if !isExists {
+		minLevel = l.minLevel
+	}
+	return msg.ResolveLevel() >= minLevel
+}
+
+// Log writes the message to every sink if it is enabled, and counts it as dropped otherwise.
+// A failing sink does not keep the message from the other sinks; the errors of all sinks are joined.
+func (l *Logger) Log(ctx context.Context, msg *LogMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.closeMu.RLock()
+	defer l.closeMu.RUnlock()
+
+	if l.closed {
+		return ErrLoggerClosed
+	}
+	if !l.Enabled(msg) {
+		l.drop(msg)
+		return nil
+	}
+	l.stampHostIdentity(msg)
+
+	var errs []error
+	for _, sink := range l.sinks {
+		if err := sink.Write(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LogBatch writes the enabled messages to every sink, with a single batch write for sinks
+// implementing BatchSink. The messages below the minimum level are dropped and counted.
+func (l *Logger) LogBatch(ctx context.Context, msgs []*LogMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.closeMu.RLock()
+	defer l.closeMu.RUnlock()
+
+	if l.closed {
+		return ErrLoggerClosed
+	}
+	enabled := make([]*LogMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		if l.Enabled(msg) {
+			l.stampHostIdentity(msg)
+			enabled = append(enabled, msg)
+		} else {
+			l.drop(msg)
+		}
+	}
+	if len(enabled) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, sink := range l.sinks {
+		if batchSink, ok := sink.(BatchSink); ok {
+			errs = append(errs, batchSink.WriteBatch(ctx, enabled))
+			continue
+		}
+		for _, msg := range enabled {
+			errs = append(errs, sink.Write(ctx, msg))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes every sink. Messages logged after Close return ErrLoggerClosed.
+func (l *Logger) Close() error {
+	l.closeMu.Lock()
+	defer l.closeMu.Unlock()
+
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+
+	var errs []error
+	for _, sink := range l.sinks {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// stampHostIdentity stamps the host identity of the Logger on the message if it has none.
+func (l *Logger) stampHostIdentity(msg *LogMessage) {
+	if l.identity != nil && !msg.hasHostIdentity() {
+		msg.StampHostIdentity(*l.identity)
+	}
+}
+
+// drop counts a dropped message.
+func (l *Logger) drop(msg *LogMessage) {
+	l.dropped.Add(1)
+	counter, _ := l.droppedByEvent.LoadOrStore(msg.EventName, new(atomic.Uint64))
+	counter.(*atomic.Uint64).Add(1)
+}
+
+// Dropped returns the number of messages dropped for being below the minimum level.
+func (l *Logger) Dropped() uint64 {
+	return l.dropped.Load()
+}
+
+// DroppedByEventName returns the number of dropped messages per EventName.
+func (l *Logger) DroppedByEventName() map[string]uint64 {
+	dropped := map[string]uint64{}
+	l.droppedByEvent.Range(func(key, value any) bool {
+		dropped[key.(string)] = value.(*atomic.Uint64).Load()
+		return true
+	})
+	return dropped
+}
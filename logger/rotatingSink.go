@@ -0,0 +1,409 @@
+// logger/rotatingSink.go
+
+package logger
+
+import (
+	"cmp"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// segmentTimeLayout names rotated segments, and is parsed back to order them.
+	segmentTimeLayout = "20060102T150405.000000000"
+	compressedSuffix  = ".gz"
+)
+
+var (
+	ErrSinkClosed = errors.New("sink is closed")
+)
+
+// RotateOption configures a RotatingFileSink.
+type RotateOption func(*RotatingFileSink)
+
+// WithMaxSize rotates the file before a write would make it larger than size bytes.
+func WithMaxSize(size int64) RotateOption {
+	return func(s *RotatingFileSink) {
+		s.maxSize = size
+	}
+}
+
+// WithRotateInterval rotates the file once it has been written for longer than interval.
+func WithRotateInterval(interval time.Duration) RotateOption {
+	return func(s *RotatingFileSink) {
+		s.interval = interval
+	}
+}
+
+// WithMaxSegments keeps at most n rotated segments, removing the oldest ones.
+func WithMaxSegments(n int) RotateOption {
+	return func(s *RotatingFileSink) {
+		s.maxSegments = n
+	}
+}
+
+// WithMaxAge removes rotated segments last written longer than age ago.
+func WithMaxAge(age time.Duration) RotateOption {
+	return func(s *RotatingFileSink) {
+		s.maxAge = age
+	}
+}
+
+// WithCompression gzip-compresses rotated segments.
+func WithCompression() RotateOption {
+	return func(s *RotatingFileSink) {
+		s.compress = true
+	}
+}
+
+// RotatingFileSink writes log messages as JSON lines to a file, and rotates the file by size or interval.
+// A rotated segment is renamed to <path>.<UTC rotation time>, gzip-compressed to <segment>.gz if enabled,
+// and the oldest segments are removed beyond the configured count or age.
+// The file and the directory are synced on rotation, so no line is lost on a crash right after it.
+// It is safe for concurrent use; rotation and compression happen in the writing goroutine.
+type RotatingFileSink struct {
+	mu       sync.Mutex
+	path     string
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	maxSize     int64
+	interval    time.Duration
+	maxSegments int
+	maxAge      time.Duration
+	compress    bool
+}
+
+// NewRotatingFileSink returns a sink appending to the file at path, which is created if it does not exist.
+// Without rotation options the file is never rotated.
+func NewRotatingFileSink(path string, opts ...RotateOption) (*RotatingFileSink, error) {
+	s := &RotatingFileSink{path: path}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// open opens the file at path for appending.
+func (s *RotatingFileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.file, s.size, s.openedAt = f, info.Size(), time.Now()
+	return nil
+}
+
+// Write writes the message as a JSON line, rotating the file first if needed.
+func (s *RotatingFileSink) Write(ctx context.Context, msg *LogMessage) error {
+	return s.WriteBatch(ctx, []*LogMessage{msg})
+}
+
+// WriteBatch writes the messages as JSON lines, rotating the file between them if needed.
+func (s *RotatingFileSink) WriteBatch(ctx context.Context, msgs []*LogMessage) error {
+	lines := make([][]byte, 0, len(msgs))
+	for _, msg := range msgs {
+		b, err := encodeJSONLine(msg, false)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, b)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return ErrSinkClosed
+	}
+	for _, b := range lines {
+		if s.shouldRotate(int64(len(b))) {
+			if err := s.rotate(); err != nil {
+				return err
+			}
+		}
+		n, err := s.file.Write(b)
+		s.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shouldRotate reports whether the file must be rotated before writing n bytes.
+// An empty file is never rotated, so a message larger than the maximum size is still written.
+func (s *RotatingFileSink) shouldRotate(n int64) bool {
+	if s.size == 0 {
+		return false
+	}
+	if s.maxSize > 0 && s.size+n > s.maxSize {
+		return true
+	}
+	return s.interval > 0 && time.Since(s.openedAt) >= s.interval
+}
+
+// Rotate rotates the file now.
+func (s *RotatingFileSink) Rotate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return ErrSinkClosed
+	}
+	return s.rotate()
+}
+
+// rotate syncs and renames the file to a new segment, opens a new file,
+// and compresses and prunes the segments. The caller must hold the lock.
+func (s *RotatingFileSink) rotate() error {
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	s.file = nil
+
+	// the file is reopened even if the rename fails, so the sink stays usable
+	segment, err := s.segmentName()
+	if err == nil {
+		err = os.Rename(s.path, segment)
+	}
+	if openErr := s.open(); openErr != nil {
+		return errors.Join(err, openErr)
+	}
+	if err != nil {
+		return err
+	}
+	if err = syncDir(filepath.Dir(s.path)); err != nil {
+		return err
+	}
+
+	if s.compress {
+		if err = compressSegment(segment); err != nil {
+			return err
+		}
+	}
+	return s.prune()
+}
+
+// segmentName returns an unused name for the next rotated segment.
+func (s *RotatingFileSink) segmentName() (string, error) {
+	base := s.path + "." + time.Now().UTC().Format(segmentTimeLayout)
+	for i := 0; ; i++ {
+		name := base
+		if i > 0 {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+		plain, err := exists(name)
+		if err != nil {
+			return "", err
+		}
+		compressed, err := exists(name + compressedSuffix)
+		if err != nil {
+			return "", err
+		}
+		if !plain && !compressed {
+			return name, nil
+		}
+	}
+}
+
+// exists reports whether a file exists at path.
+func exists(path string) (bool, error) {
+	_, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// segment is a rotated segment found next to the file.
+type segment struct {
+	name      string
+	rotatedAt time.Time
+	index     int
+}
+
+// parseSegment parses a file name of the form <base>.<segmentTimeLayout>[-N][.gz],
+// as written by segmentName. It returns false for any other name.
+func parseSegment(name, base string) (segment, bool) {
+	rest, isSegment := strings.CutPrefix(name, base+".")
+	if !isSegment {
+		return segment{}, false
+	}
+	rest = strings.TrimSuffix(rest, compressedSuffix)
+	if len(rest) < len(segmentTimeLayout) {
+		return segment{}, false
+	}
+	rotatedAt, err := time.ParseInLocation(segmentTimeLayout, rest[:len(segmentTimeLayout)], time.UTC)
+	if err != nil {
+		return segment{}, false
+	}
+
+	index := 0
+	if suffix := rest[len(segmentTimeLayout):]; suffix != "" {
+		number, isIndexed := strings.CutPrefix(suffix, "-")
+		if !isIndexed {
+			return segment{}, false
+		}
+		// the index is written without sign or leading zeros
+		index, err = strconv.Atoi(number)
+		if err != nil || index <= 0 || strconv.Itoa(index) != number {
+			return segment{}, false
+		}
+	}
+	return segment{name: name, rotatedAt: rotatedAt, index: index}, true
+}
+
+// Segments returns the paths of the rotated segments, oldest first.
+// Only files named like the segments of this sink are returned, other files of the directory are ignored.
+func (s *RotatingFileSink) Segments() ([]string, error) {
+	dir := filepath.Dir(s.path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	base := filepath.Base(s.path)
+	var found []segment
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if seg, isSegment := parseSegment(entry.Name(), base); isSegment {
+			found = append(found, seg)
+		}
+	}
+	slices.SortFunc(found, func(a, b segment) int {
+		if c := a.rotatedAt.Compare(b.rotatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.index, b.index)
+	})
+
+	segments := make([]string, 0, len(found))
+	for _, seg := range found {
+		segments = append(segments, filepath.Join(dir, seg.name))
+	}
+	return segments, nil
+}
+
+// prune removes the segments beyond the maximum count or age.
+func (s *RotatingFileSink) prune() error {
+	if s.maxSegments <= 0 && s.maxAge <= 0 {
+		return nil
+	}
+	segments, err := s.Segments()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, segment := range segments {
+		remove := s.maxSegments > 0 && len(segments)-i > s.maxSegments
+		if !remove && s.maxAge > 0 {
+			info, err := os.Stat(segment)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			remove = time.Since(info.ModTime()) > s.maxAge
+		}
+		if remove {
+			if err := os.Remove(segment); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close syncs and closes the file. Writes after Close return ErrSinkClosed.
+func (s *RotatingFileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := errors.Join(s.file.Sync(), s.file.Close())
+	s.file = nil
+	return err
+}
+
+// compressSegment gzip-compresses a segment to <segment>.gz, keeping its modification time,
+// and removes the uncompressed segment once the compressed one is synced.
+func compressSegment(segment string) (err error) {
+	src, err := os.Open(segment)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(segment+compressedSuffix, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			dst.Close()
+			os.Remove(dst.Name())
+		}
+	}()
+
+	zw := gzip.NewWriter(dst)
+	zw.Name = filepath.Base(segment)
+	zw.ModTime = info.ModTime()
+	if _, err = io.Copy(zw, src); err != nil {
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		return err
+	}
+	if err = dst.Sync(); err != nil {
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	if err = os.Chtimes(dst.Name(), info.ModTime(), info.ModTime()); err != nil {
+		return err
+	}
+	return os.Remove(segment)
+}
+
+// syncDir syncs a directory, so that renames and new files in it are durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,202 @@
+package logger_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enki-polvo/polvo-logger/logger"
+)
+
+// countLines returns the number of lines of a log file, decompressing it if needed.
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open %v: %v", path, err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("Failed to decompress %v: %v", path, err)
+		}
+		r = zr
+	}
+
+	lines := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines++
+	}
+	if err = scanner.Err(); err != nil {
+		t.Fatalf("Failed to read %v: %v", path, err)
+	}
+	return lines
+}
+
+// Test size-based rotation from many goroutines
+func TestRotatingFileSinkBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	sink, err := logger.NewRotatingFileSink(path, logger.WithMaxSize(1024), logger.WithCompression())
+	if err != nil {
+		t.Fatalf("Failed to create rotating file sink: %v", err)
+	}
+
+	const goroutines, messages = 8, 50
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < messages; j++ {
+				msg := &logger.LogMessage{EventName: "ProcessCreate", Source: "eBPF", Log: "process created"}
+				if err := sink.Write(context.Background(), msg); err != nil {
+					t.Errorf("Failed to write message: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err = sink.Close(); err != nil {
+		t.Fatalf("Failed to close sink: %v", err)
+	}
+
+	segments, err := sink.Segments()
+	if err != nil {
+		t.Fatalf("Failed to list segments: %v", err)
+	}
+	if len(segments) == 0 {
+		t.Fatal("File was not rotated")
+	}
+
+	// no line is lost across the rotations
+	lines := countLines(t, path)
+	for _, segment := range segments {
+		if !strings.HasSuffix(segment, ".gz") {
+			t.Fatalf("Segment is not compressed: %v", segment)
+		}
+		lines += countLines(t, segment)
+	}
+	if lines != goroutines*messages {
+		t.Fatalf("Unexpected number of lines: got %d, want %d", lines, goroutines*messages)
+	}
+}
+
+// Test interval-based rotation and the retention of segments
+func TestRotatingFileSinkRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	sink, err := logger.NewRotatingFileSink(path,
+		logger.WithRotateInterval(time.Millisecond),
+		logger.WithMaxSegments(2),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create rotating file sink: %v", err)
+	}
+	defer sink.Close()
+
+	for i := 0; i < 5; i++ {
+		time.Sleep(2 * time.Millisecond)
+		if err = sink.Write(context.Background(), &logger.LogMessage{EventName: "TcpEvent", Log: "connected"}); err != nil {
+			t.Fatalf("Failed to write message: %v", err)
+		}
+	}
+
+	segments, err := sink.Segments()
+	if err != nil {
+		t.Fatalf("Failed to list segments: %v", err)
+	}
+	if len(segments) != 2 {
+		t.Fatalf("Unexpected number of kept segments: got %v, want 2", segments)
+	}
+	if lines := countLines(t, path); lines != 1 {
+		t.Fatalf("Current file was not rotated by interval: %d lines", lines)
+	}
+
+	// segments older than the maximum age are removed on the next rotation
+	old := time.Now().Add(-48 * time.Hour)
+	if err = os.Chtimes(segments[0], old, old); err != nil {
+		t.Fatalf("Failed to age segment: %v", err)
+	}
+	sink2, err := logger.NewRotatingFileSink(path, logger.WithMaxAge(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to create rotating file sink: %v", err)
+	}
+	defer sink2.Close()
+	if err = sink2.Rotate(); err != nil {
+		t.Fatalf("Failed to rotate: %v", err)
+	}
+	if _, err = os.Stat(segments[0]); !os.IsNotExist(err) {
+		t.Fatalf("Aged segment was not removed: %v", err)
+	}
+}
+
+// Test that only the files named like segments are listed and pruned
+// Test that segments are ordered by their rotation time and index, not by name
+func TestRotatingFileSinkIgnoresSiblings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.log")
+	siblings := []string{
+		"events.log.bak",
+		"events.log.json",
+		"events.log.1",
+		"events.log.20250101T000000.000000000.txt",
+		"events.log.20250101T000000.000000000-01",
+		"events.logger",
+	}
+	segments := []string{
+		"events.log.20250101T000000.000000000",
+		"events.log.20250101T000000.000000000-2.gz",
+		"events.log.20250101T000000.000000000-10",
+	}
+	for _, name := range append(slices.Clone(siblings), segments...) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}\n"), 0o644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	sink, err := logger.NewRotatingFileSink(path, logger.WithMaxSegments(1))
+	if err != nil {
+		t.Fatalf("Failed to create rotating file sink: %v", err)
+	}
+	defer sink.Close()
+
+	listed, err := sink.Segments()
+	if err != nil {
+		t.Fatalf("Failed to list segments: %v", err)
+	}
+	if len(listed) != len(segments) {
+		t.Fatalf("Unexpected segments: got %v, want %v", listed, segments)
+	}
+	for i, segment := range segments {
+		if listed[i] != filepath.Join(dir, segment) {
+			t.Fatalf("Unexpected segment at %d: got %v, want %v", i, listed[i], segment)
+		}
+	}
+
+	if err = sink.Write(context.Background(), &logger.LogMessage{EventName: "TcpEvent", Log: "connected"}); err != nil {
+		t.Fatalf("Failed to write message: %v", err)
+	}
+	if err = sink.Rotate(); err != nil {
+		t.Fatalf("Failed to rotate: %v", err)
+	}
+	if listed, err = sink.Segments(); err != nil || len(listed) != 1 || slices.Contains(segments, filepath.Base(listed[0])) {
+		t.Fatalf("Old segments were not pruned: %v, %v", listed, err)
+	}
+	for _, name := range siblings {
+		if _, err = os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("Unrelated file was removed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// logger/sink.go
+
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// Sink is a destination of log messages.
+type Sink interface {
+	// Write writes a log message. It must be safe for concurrent use.
+	Write(ctx context.Context, msg *LogMessage) error
+	// Close releases the resources of the sink. Messages must not be written after Close.
+	Close() error
+}
+
+// BatchSink is implemented by sinks that write several messages at once more efficiently,
+// e.g. with a single write to the underlying writer.
+type BatchSink interface {
+	Sink
+	// WriteBatch writes the messages in order.
+	WriteBatch(ctx context.Context, msgs []*LogMessage) error
+}
+
+// SinkOption configures a WriterSink.
+type SinkOption func(*WriterSink)
+
+// WithPrettyJSON makes the sink write pretty-printed JSON instead of one line per message.
+func WithPrettyJSON() SinkOption {
+	return func(s *WriterSink) {
+		s.pretty = true
+	}
+}
+
+// WriterSink writes log messages as JSON to an io.Writer, one message per line.
+// It is safe for concurrent use.
+type WriterSink struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer // closed by Close, if set
+	pretty bool
+}
+
+// NewWriterSink returns a sink writing to w. Close does not close w.
+func NewWriterSink(w io.Writer, opts ...SinkOption) *WriterSink {
+	s := &WriterSink{w: w}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// NewStdoutSink returns a sink writing to the standard output.
+func NewStdoutSink(opts ...SinkOption) *WriterSink {
+	return NewWriterSink(os.Stdout, opts...)
+}
+
+// NewStderrSink returns a sink writing to the standard error.
+func NewStderrSink(opts ...SinkOption) *WriterSink {
+	return NewWriterSink(os.Stderr, opts...)
+}
+
+// NewFileSink returns a sink appending to the file at path, which is created if it does not exist.
+// Close closes the file.
+func NewFileSink(path string, opts ...SinkOption) (*WriterSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	s := NewWriterSink(f, opts...)
+	s.closer = f
+	return s, nil
+}
+
+// Write writes the message as JSON followed by a newline.
+func (s *WriterSink) Write(ctx context.Context, msg *LogMessage) error {
+	b, err := encodeJSONLine(msg, s.pretty)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.w.Write(b)
+	return err
+}
+
+// WriteBatch writes the messages as JSON lines with a single write.
+func (s *WriterSink) WriteBatch(ctx context.Context, msgs []*LogMessage) error {
+	var buf []byte
+	for _, msg := range msgs {
+		b, err := encodeJSONLine(msg, s.pretty)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, b...)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.w.Write(buf)
+	return err
+}
+
+// encodeJSONLine returns the JSON encoding of the message followed by a newline.
+func encodeJSONLine(msg *LogMessage, pretty bool) ([]byte, error) {
+	var b []byte
+	var err error
+	if pretty {
+		b, err = json.MarshalIndent(msg, "", "  ")
+	} else {
+		b, err = json.Marshal(msg)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// Close closes the underlying file of a file sink. It does nothing for other sinks.
+func (s *WriterSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
@@ -0,0 +1,121 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/enki-polvo/polvo-logger/logger"
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+)
+
+// failingSink fails every write.
+type failingSink struct{}
+
+func (failingSink) Write(context.Context, *logger.LogMessage) error { return errors.New("disk full") }
+func (failingSink) Close() error                                    { return nil }
+
+// Test that a message is routed to every sink
+func TestLoggerSinks(t *testing.T) {
+	var buf bytes.Buffer
+	path := filepath.Join(t.TempDir(), "events.log")
+	fileSink, err := logger.NewFileSink(path)
+	if err != nil {
+		t.Fatalf("Failed to create file sink: %v", err)
+	}
+	l := logger.NewLogger(logger.WithSink(logger.NewWriterSink(&buf), fileSink))
+
+	msg, err := logger.BuildLog("eBPF", "ProcessCreate", "process created", "", map[string]any{"pid": 1234})
+	if err != nil {
+		t.Fatalf("Failed to build log: %v", err)
+	}
+	if err = l.Log(context.Background(), msg); err != nil {
+		t.Fatalf("Failed to log message: %v", err)
+	}
+	if err = l.Close(); err != nil {
+		t.Fatalf("Failed to close logger: %v", err)
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	for _, out := range []string{buf.String(), string(written)} {
+		decoded := logger.LogMessage{}
+		if err = json.Unmarshal([]byte(out), &decoded); err != nil || !strings.HasSuffix(out, "}\n") {
+			t.Fatalf("Failed to decode written message %q: %v", out, err)
+		}
+		if decoded.EventName != "ProcessCreate" || decoded.Log != "process created" {
+			t.Fatalf("Written message does not match: %+v", decoded)
+		}
+	}
+
+	if err = l.Log(context.Background(), msg); !errors.Is(err, logger.ErrLoggerClosed) {
+		t.Fatalf("Expected ErrLoggerClosed after Close, got %v", err)
+	}
+}
+
+// Test that a failing sink does not keep messages from the other sinks
+func TestLoggerFailingSink(t *testing.T) {
+	var buf bytes.Buffer
+	l := logger.NewLogger(logger.WithSink(failingSink{}, logger.NewWriterSink(&buf)))
+
+	err := l.Log(context.Background(), &logger.LogMessage{EventName: "ProcessCreate", Log: "process created"})
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("Expected the error of the failing sink, got %v", err)
+	}
+	if buf.Len() == 0 {
+		t.Fatal("Message was not written to the working sink")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err = l.Log(ctx, &logger.LogMessage{EventName: "ProcessCreate"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
+
+// Test that every Logger stamps its own host identity on the messages without one
+func TestLoggerHostIdentity(t *testing.T) {
+	identity := commonModel.HostIdentity{HostID: "abcdef", Hostname: "web-01", BootID: "1234", AgentVersion: "1.4.0"}
+	sink := newOpenSink()
+	l := logger.NewLogger(logger.WithSink(sink), logger.WithHostIdentity(identity))
+	other := logger.NewLogger(logger.WithSink(newOpenSink()))
+
+	built, err := l.BuildLog("eBPF", "ProcessCreate", "process created", "", map[string]any{})
+	if err != nil {
+		t.Fatalf("Failed to build log: %v", err)
+	}
+	if built.HostID != identity.HostID || built.AgentVersion != identity.AgentVersion {
+		t.Fatalf("Built message has no host identity: %+v", built)
+	}
+	unstamped, err := other.BuildLog("eBPF", "ProcessCreate", "process created", "", map[string]any{})
+	if err != nil {
+		t.Fatalf("Failed to build log: %v", err)
+	}
+	if unstamped.HostID != "" {
+		t.Fatalf("Logger without host identity stamped one: %+v", unstamped)
+	}
+
+	// messages built elsewhere are stamped when written, unless they have an identity
+	slog.New(logger.NewSlogHandler(l)).Error("from slog")
+	foreign := buildAsyncLog(t, "ProcessCreate", "foreign")
+	foreign.Hostname = "db-01"
+	if err = l.Log(context.Background(), foreign); err != nil {
+		t.Fatalf("Failed to log message: %v", err)
+	}
+
+	written := sink.Written()
+	if len(written) != 2 || written[0].Hostname != identity.Hostname || written[0].BootID != identity.BootID {
+		t.Fatalf("Written slog message has no host identity: %+v", written)
+	}
+	if written[1].Hostname != "db-01" || written[1].HostID != "" {
+		t.Fatalf("Host identity of the message was overwritten: %+v", written[1])
+	}
+}
@@ -0,0 +1,242 @@
+// logger/slogHandler.go
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+const (
+	// SLOG_EVENT_NAME_KEY is the key of the slog attribute mapped to the EventName of a log message.
+	SLOG_EVENT_NAME_KEY = "eventname"
+	// SLOG_SOURCE_KEY is the key of the slog attribute mapped to the Source of a log message.
+	SLOG_SOURCE_KEY = "source"
+
+	// DEFAULT_SLOG_EVENT_NAME is the EventName of the records without a SLOG_EVENT_NAME_KEY attribute.
+	DEFAULT_SLOG_EVENT_NAME = "Log"
+	// DEFAULT_SLOG_SOURCE is the Source of the records without a SLOG_SOURCE_KEY attribute.
+	DEFAULT_SLOG_SOURCE = "slog"
+)
+
+// SlogLevel returns the slog level of the level. LEVEL_UNSET maps to the level of DEFAULT_LEVEL.
+func (l Level) SlogLevel() slog.Level {
+	switch l {
+	case LEVEL_DEBUG:
+		return slog.LevelDebug
+	case LEVEL_WARNING:
+		return slog.LevelWarn
+	case LEVEL_ERROR:
+		return slog.LevelError
+	case LEVEL_CRITICAL:
+		return slog.LevelError + 4
+	case LEVEL_UNSET:
+		return DEFAULT_LEVEL.SlogLevel()
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// LevelFromSlog returns the level of a slog level. Levels above slog.LevelError map to LEVEL_CRITICAL.
+func LevelFromSlog(level slog.Level) Level {
+	switch {
+	case level < slog.LevelInfo:
+		return LEVEL_DEBUG
+	case level < slog.LevelWarn:
+		return LEVEL_INFO
+	case level < slog.LevelError:
+		return LEVEL_WARNING
+	case level == slog.LevelError:
+		return LEVEL_ERROR
+	default:
+		return LEVEL_CRITICAL
+	}
+}
+
+// MessageLogger logs unified log messages. It is implemented by Logger and AsyncLogger.
+type MessageLogger interface {
+	Log(ctx context.Context, msg *LogMessage) error
+}
+
+// SlogOption configures a SlogHandler created by NewSlogHandler.
+type SlogOption func(*SlogHandler)
+
+// WithSlogEventName sets the EventName of the records without a SLOG_EVENT_NAME_KEY attribute.
+// The default is DEFAULT_SLOG_EVENT_NAME.
+func WithSlogEventName(eventName string) SlogOption {
+	return func(h *SlogHandler) {
+		h.eventName = eventName
+	}
+}
+
+// WithSlogSource sets the Source of the records without a SLOG_SOURCE_KEY attribute.
+// The default is DEFAULT_SLOG_SOURCE.
+func WithSlogSource(source string) SlogOption {
+	return func(h *SlogHandler) {
+		h.source = source
+	}
+}
+
+// WithSlogLevel sets the minimum level of the records the handler passes to the logger.
+// By default, every record is passed and the logger filters them by level.
+func WithSlogLevel(level slog.Leveler) SlogOption {
+	return func(h *SlogHandler) {
+		h.minLevel = level
+	}
+}
+
+// groupedAttrs are attributes added by WithAttrs within the groups opened before.
+type groupedAttrs struct {
+	groups []string
+	attrs  []slog.Attr
+}
+
+// SlogHandler is a slog.Handler logging every record as a unified log message:
+//   - the message of the record is the Log,
+//   - the SLOG_EVENT_NAME_KEY and SLOG_SOURCE_KEY attributes outside of groups are the EventName and Source,
+//   - the other attributes are the Metadata, groups being nested maps.
+//
+// The host identity is stamped by the Logger the messages are written to, see WithHostIdentity.
+type SlogHandler struct {
+	logger    MessageLogger
+	eventName string
+	source    string
+	minLevel  slog.Leveler
+
+	attrs  []groupedAttrs
+	groups []string
+}
+
+// NewSlogHandler returns a slog.Handler logging to l, e.g. a Logger or an AsyncLogger.
+func NewSlogHandler(l MessageLogger, opts ...SlogOption) *SlogHandler {
+	h := &SlogHandler{
+		logger:    l,
+		eventName: DEFAULT_SLOG_EVENT_NAME,
+		source:    DEFAULT_SLOG_SOURCE,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Enabled implements slog.Handler.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.minLevel == nil || level >= h.minLevel.Level()
+}
+
+// Handle implements slog.Handler.
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	timestamp := record.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	msg := &LogMessage{
+		EventName: h.eventName,
+		Source:    h.source,
+		Timestamp: timestamp.Format(time.RFC3339Nano),
+		Log:       record.Message,
+		Metadata:  map[string]any{},
+		Level:     LevelFromSlog(record.Level),
+	}
+
+	for _, grouped := range h.attrs {
+		for _, attr := range grouped.attrs {
+			addAttr(msg, grouped.groups, attr)
+		}
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		addAttr(msg, h.groups, attr)
+		return true
+	})
+	return h.logger.Log(ctx, msg)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	clone := *h
+	clone.attrs = append(slices.Clip(h.attrs), groupedAttrs{groups: h.groups, attrs: slices.Clone(attrs)})
+	return &clone
+}
+
+// WithGroup implements slog.Handler.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(slices.Clip(h.groups), name)
+	return &clone
+}
+
+// addAttr adds an attribute within the groups to the message.
+func addAttr(msg *LogMessage, groups []string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if len(groups) == 0 && attr.Value.Kind() != slog.KindGroup {
+		switch attr.Key {
+		case SLOG_EVENT_NAME_KEY:
+			msg.EventName = attr.Value.String()
+			return
+		case SLOG_SOURCE_KEY:
+			msg.Source = attr.Value.String()
+			return
+		}
+	}
+
+	metadata := msg.Metadata
+	for _, group := range groups {
+		metadata = subMetadata(metadata, group)
+	}
+	addMetadata(metadata, attr)
+}
+
+// addMetadata adds an attribute to the metadata, groups being nested maps.
+func addMetadata(metadata map[string]any, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() != slog.KindGroup {
+		metadata[attr.Key] = metadataValue(attr.Value)
+		return
+	}
+
+	members := attr.Value.Group()
+	if len(members) == 0 {
+		return
+	}
+	// the members of a group without a key are inlined
+	if attr.Key != "" {
+		metadata = subMetadata(metadata, attr.Key)
+	}
+	for _, member := range members {
+		addMetadata(metadata, member)
+	}
+}
+
+// subMetadata returns the nested map of the group, creating it if needed.
+func subMetadata(metadata map[string]any, group string) map[string]any {
+	sub, ok := metadata[group].(map[string]any)
+	if !ok {
+		sub = map[string]any{}
+		metadata[group] = sub
+	}
+	return sub
+}
+
+// metadataValue returns the value of a resolved non-group attribute as stored in the metadata.
+func metadataValue(value slog.Value) any {
+	if err, ok := value.Any().(error); ok {
+		// errors have no exported fields, and would be encoded as {}
+		return err.Error()
+	}
+	return value.Any()
+}
@@ -0,0 +1,110 @@
+package logger_test
+
+import (
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/enki-polvo/polvo-logger/logger"
+)
+
+// newOpenSink returns a gatedSink that never blocks.
+func newOpenSink() *gatedSink {
+	sink := newGatedSink()
+	close(sink.gate)
+	return sink
+}
+
+// Test that records are logged as unified log messages
+func TestSlogHandler(t *testing.T) {
+	sink := newOpenSink()
+	l := logger.NewLogger(logger.WithSink(sink), logger.WithMinLevel(logger.LEVEL_DEBUG))
+	slogger := slog.New(logger.NewSlogHandler(l, logger.WithSlogSource("api")))
+
+	slogger.With("service", "auth").WithGroup("request").With("method", "POST").
+		Warn("login failed",
+			slog.String(logger.SLOG_EVENT_NAME_KEY, "LoginFailure"),
+			slog.Group("client", "ip", "10.0.0.1", "port", 51234),
+			slog.Group("", "inlined", true),
+			slog.Group("empty"),
+			slog.Any("err", errors.New("bad password")))
+	slogger.Info("ready")
+
+	written := sink.Written()
+	if len(written) != 2 {
+		t.Fatalf("Expected 2 written messages, got %d", len(written))
+	}
+
+	msg := written[0]
+	if msg.Log != "login failed" || msg.Source != "api" || msg.Level != logger.LEVEL_WARNING {
+		t.Fatalf("Written message does not match: %+v", msg)
+	}
+	// the event name attribute is within the request group, so it is metadata
+	want := map[string]any{
+		"service": "auth",
+		"request": map[string]any{
+			"method":                   "POST",
+			logger.SLOG_EVENT_NAME_KEY: "LoginFailure",
+			"client":                   map[string]any{"ip": "10.0.0.1", "port": int64(51234)},
+			"inlined":                  true,
+			"err":                      "bad password",
+		},
+	}
+	if !reflect.DeepEqual(msg.Metadata, want) {
+		t.Fatalf("Metadata does not match: got %v, want %v", msg.Metadata, want)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, msg.Timestamp); err != nil {
+		t.Fatalf("Failed to parse timestamp: %v", err)
+	}
+
+	if written[1].EventName != logger.DEFAULT_SLOG_EVENT_NAME || written[1].Level != logger.LEVEL_INFO {
+		t.Fatalf("Written message does not match: %+v", written[1])
+	}
+}
+
+// Test that the special attributes outside of groups set the EventName and Source
+func TestSlogHandlerSpecialAttrs(t *testing.T) {
+	sink := newOpenSink()
+	l := logger.NewLogger(logger.WithSink(sink))
+	h := logger.NewSlogHandler(l, logger.WithSlogLevel(slog.LevelInfo))
+	slogger := slog.New(h).With(logger.SLOG_SOURCE_KEY, "eBPF")
+
+	slogger.Debug("ignored")
+	slogger.Error("process crashed", logger.SLOG_EVENT_NAME_KEY, "ProcessTerminate", "pid", 1234)
+
+	written := sink.Written()
+	if len(written) != 1 {
+		t.Fatalf("Expected 1 written message, got %d", len(written))
+	}
+	msg := written[0]
+	if msg.EventName != "ProcessTerminate" || msg.Source != "eBPF" || msg.Level != logger.LEVEL_ERROR {
+		t.Fatalf("Written message does not match: %+v", msg)
+	}
+	if !reflect.DeepEqual(msg.Metadata, map[string]any{"pid": int64(1234)}) {
+		t.Fatalf("Metadata does not match: %v", msg.Metadata)
+	}
+}
+
+func TestLevelFromSlog(t *testing.T) {
+	tests := []struct {
+		slogLevel slog.Level
+		level     logger.Level
+	}{
+		{slog.LevelDebug - 4, logger.LEVEL_DEBUG},
+		{slog.LevelDebug, logger.LEVEL_DEBUG},
+		{slog.LevelInfo, logger.LEVEL_INFO},
+		{slog.LevelWarn, logger.LEVEL_WARNING},
+		{slog.LevelError, logger.LEVEL_ERROR},
+		{slog.LevelError + 4, logger.LEVEL_CRITICAL},
+	}
+	for _, test := range tests {
+		if level := logger.LevelFromSlog(test.slogLevel); level != test.level {
+			t.Fatalf("Level of %v does not match: got %v, want %v", test.slogLevel, level, test.level)
+		}
+		if test.slogLevel >= slog.LevelDebug && test.level.SlogLevel() != test.slogLevel {
+			t.Fatalf("Slog level of %v does not match: got %v, want %v", test.level, test.level.SlogLevel(), test.slogLevel)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// logger/slogSink.go
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+)
+
+// SlogSink is a Sink sending log messages to a slog.Handler, the reverse of SlogHandler:
+//   - the Log is the message of the record, and the resolved level its level,
+//   - the EventName and Source are the SLOG_EVENT_NAME_KEY and SLOG_SOURCE_KEY attributes,
+//   - the host identity, if set, is added as attributes named like its JSON fields,
+//   - the Metadata entries are attributes sorted by key, nested maps being groups.
+type SlogSink struct {
+	handler slog.Handler
+}
+
+// NewSlogSink returns a sink sending log messages to handler. Close does not close the handler.
+func NewSlogSink(handler slog.Handler) *SlogSink {
+	return &SlogSink{handler: handler}
+}
+
+// Write sends the message to the handler if it is enabled for the level of the message.
+// A timestamp that does not parse as RFC3339 is replaced by the current time.
+func (s *SlogSink) Write(ctx context.Context, msg *LogMessage) error {
+	level := msg.ResolveLevel().SlogLevel()
+	if !s.handler.Enabled(ctx, level) {
+		return nil
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, msg.Timestamp)
+	if err != nil {
+		timestamp = time.Now()
+	}
+	record := slog.NewRecord(timestamp, level, msg.Log, 0)
+	record.AddAttrs(
+		slog.String(SLOG_EVENT_NAME_KEY, msg.EventName),
+		slog.String(SLOG_SOURCE_KEY, msg.Source),
+	)
+	for _, field := range []struct{ key, value string }{
+		{"hostid", msg.HostID},
+		{"hostname", msg.Hostname},
+		{"bootid", msg.BootID},
+		{"agentversion", msg.AgentVersion},
+	} {
+		if field.value != "" {
+			record.AddAttrs(slog.String(field.key, field.value))
+		}
+	}
+	record.AddAttrs(metadataAttrs(msg.Metadata)...)
+	return s.handler.Handle(ctx, record)
+}
+
+// Close implements Sink. It does nothing, as slog handlers have no Close.
+func (s *SlogSink) Close() error {
+	return nil
+}
+
+// metadataAttrs returns the metadata entries as attributes sorted by key, nested maps being groups.
+func metadataAttrs(metadata map[string]any) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(metadata))
+	for _, key := range slices.Sorted(maps.Keys(metadata)) {
+		if nested, ok := metadata[key].(map[string]any); ok {
+			attrs = append(attrs, slog.Attr{Key: key, Value: slog.GroupValue(metadataAttrs(nested)...)})
+			continue
+		}
+		attrs = append(attrs, slog.Any(key, metadata[key]))
+	}
+	return attrs
+}
@@ -0,0 +1,75 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/enki-polvo/polvo-logger/logger"
+)
+
+// Test that log messages are sent to a slog.Handler
+func TestSlogSink(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+	l := logger.NewLogger(logger.WithSink(logger.NewSlogSink(handler)), logger.WithMinLevel(logger.LEVEL_DEBUG))
+
+	msg, err := logger.BuildLog("eBPF", "TcpEvent", "connection opened", "2024-05-01T12:00:00.123456Z",
+		map[string]any{"pid": 1234, "conn": map[string]any{"dport": 443}})
+	if err != nil {
+		t.Fatalf("Failed to build log: %v", err)
+	}
+	if err = l.Log(context.Background(), msg); err != nil {
+		t.Fatalf("Failed to log message: %v", err)
+	}
+	// FileOpenEvent defaults to the debug level, which the handler does not enable
+	debug, err := logger.BuildLog("eBPF", "FileOpenEvent", "file opened", "", nil)
+	if err != nil {
+		t.Fatalf("Failed to build log: %v", err)
+	}
+	if err = l.Log(context.Background(), debug); err != nil {
+		t.Fatalf("Failed to log message: %v", err)
+	}
+
+	var record map[string]any
+	if err = json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Failed to decode record %q: %v", buf.String(), err)
+	}
+	want := map[string]any{
+		"time":                     "2024-05-01T12:00:00.123456Z",
+		"level":                    "INFO",
+		"msg":                      "connection opened",
+		logger.SLOG_EVENT_NAME_KEY: "TcpEvent",
+		logger.SLOG_SOURCE_KEY:     "eBPF",
+		"pid":                      float64(1234),
+		"conn":                     map[string]any{"dport": float64(443)},
+	}
+	if !reflect.DeepEqual(record, want) {
+		t.Fatalf("Record does not match: got %v, want %v", record, want)
+	}
+}
+
+// Test that a message sent through a SlogSink into a SlogHandler comes out unchanged
+func TestSlogRoundTrip(t *testing.T) {
+	sink := newOpenSink()
+	inner := logger.NewLogger(logger.WithSink(sink), logger.WithMinLevel(logger.LEVEL_DEBUG))
+	outer := logger.NewLogger(logger.WithSink(logger.NewSlogSink(logger.NewSlogHandler(inner))))
+
+	msg, err := logger.BuildLog("eBPF", "ProcessCreate", "process created", "2024-05-01T12:00:00Z",
+		map[string]any{"image": "/bin/bash", "parent": map[string]any{"pid": int64(1)}})
+	if err != nil {
+		t.Fatalf("Failed to build log: %v", err)
+	}
+	msg.Level = logger.LEVEL_WARNING
+	if err = outer.Log(context.Background(), msg); err != nil {
+		t.Fatalf("Failed to log message: %v", err)
+	}
+
+	written := sink.Written()
+	if len(written) != 1 || !reflect.DeepEqual(written[0], msg) {
+		t.Fatalf("Round-tripped message does not match: got %+v, want %+v", written, msg)
+	}
+}
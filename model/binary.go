@@ -0,0 +1,770 @@
+// model/binary.go
+package commonModel
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BINARY_FORMAT_VERSION is the version of the binary encoding written by MarshalBinary.
+//
+// An encoded event is laid out as follows:
+//
+//	version    byte
+//	length     uvarint, number of bytes that follow
+//	strings    uvarint count, then each string as uvarint length and bytes
+//	header     struct value of CommonHeader
+//	metadata   struct value of the metadata, or nil
+//
+// Every value starts with a kind byte. Integers are varints, strings are indexes into the
+// interned string table, timestamps are varint nanoseconds since the Unix epoch followed by
+// their zone offset in seconds, and structs are a uvarint field count followed by the values
+// of their exported fields in declaration order.
+// Decoders skip trailing fields they do not know and leave missing fields at their zero value.
+const BINARY_FORMAT_VERSION byte = 1
+
+// DEFAULT_MAX_BINARY_FRAME_SIZE is the largest frame, in bytes after the length prefix, read by ReadBinaryFrame.
+const DEFAULT_MAX_BINARY_FRAME_SIZE = 1 << 20
+
+const (
+	ErrBinaryVersion     = "unsupported binary format version %d"
+	ErrBinaryKind        = "binary field '%s': expected %s, got %s"
+	ErrBinaryUnsupported = "binary field '%s': unsupported type %v"
+	ErrBinaryOverflow    = "binary field '%s': value %v overflows %v"
+	ErrBinaryString      = "binary field '%s': string index %d out of range"
+	ErrBinaryUnknownKind = "unknown binary kind %d"
+	ErrBinaryFrameSize   = "binary frame of %d bytes exceeds the maximum of %d bytes"
+)
+
+var (
+	ErrBinaryTruncated = errors.New("truncated binary event")
+	ErrBinaryTrailing  = errors.New("trailing bytes after binary event")
+)
+
+// binaryKind tags every encoded value.
+type binaryKind byte
+
+const (
+	binaryNil binaryKind = iota
+	binaryInt
+	binaryUint
+	binaryString
+	binaryBool
+	binaryFloat
+	binaryTime
+	binaryStruct
+	binaryList
+)
+
+func (k binaryKind) String() string {
+	switch k {
+	case binaryNil:
+		return "nil"
+	case binaryInt:
+		return "int"
+	case binaryUint:
+		return "uint"
+	case binaryString:
+		return "string"
+	case binaryBool:
+		return "bool"
+	case binaryFloat:
+		return "float"
+	case binaryTime:
+		return "time"
+	case binaryStruct:
+		return "struct"
+	case binaryList:
+		return "list"
+	default:
+		return fmt.Sprintf("kind(%d)", byte(k))
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (m *CommonModel) MarshalBinary() ([]byte, error) {
+	enc := binaryEncoders.Get().(*binaryEncoder)
+	defer enc.release()
+
+	if err := enc.encodeValue(reflect.ValueOf(&m.CommonHeader).Elem()); err != nil {
+		return nil, withBinaryPath(err, "CommonHeader")
+	}
+	if err := enc.encodeValue(reflect.ValueOf(m.Metadata)); err != nil {
+		return nil, withBinaryPath(err, "Metadata")
+	}
+	return enc.bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// Metadata is decoded into the structure registered for the EventCode. If Metadata already
+// holds a structure of the right type, as it does for objects allocated from eventPool.Pool,
+// it is reset and reused.
+func (m *CommonModel) UnmarshalBinary(data []byte) error {
+	dec := binaryDecoders.Get().(*binaryDecoder)
+	defer dec.release()
+
+	if err := dec.readHeader(data); err != nil {
+		return err
+	}
+	return m.decodeBinaryMetadata(dec)
+}
+
+// UnmarshalBinaryFrom decodes a binary encoded event into a CommonModel obtained from the allocator.
+func UnmarshalBinaryFrom(data []byte, allocator Allocator) (*CommonModel, error) {
+	dec := binaryDecoders.Get().(*binaryDecoder)
+	defer dec.release()
+
+	if err := dec.readHeader(data); err != nil {
+		return nil, err
+	}
+
+	model, err := allocator.Allocate(dec.header.EventCode)
+	if err != nil {
+		return nil, err
+	}
+	if err = model.decodeBinaryMetadata(dec); err != nil {
+		// the allocated model is not returned, give it back
+		return nil, errors.Join(err, allocator.Free(model))
+	}
+	return model, nil
+}
+
+// ReadBinaryFrame reads a single binary encoded event from r, using its length prefix.
+// Frames larger than DEFAULT_MAX_BINARY_FRAME_SIZE are rejected.
+func ReadBinaryFrame(r *bufio.Reader) ([]byte, error) {
+	return ReadBinaryFrameSize(r, DEFAULT_MAX_BINARY_FRAME_SIZE)
+}
+
+// ReadBinaryFrameSize reads a single binary encoded event from r, using its length prefix.
+// Frames longer than maxSize bytes after the prefix are rejected before they are allocated,
+// since the prefix of a corrupt stream may hold any length.
+func ReadBinaryFrameSize(r *bufio.Reader, maxSize int) ([]byte, error) {
+	version, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if version != BINARY_FORMAT_VERSION {
+		return nil, fmt.Errorf(ErrBinaryVersion, version)
+	}
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if maxSize < 0 || length > uint64(maxSize) {
+		return nil, fmt.Errorf(ErrBinaryFrameSize, length, maxSize)
+	}
+
+	prefix := 1 + uvarintLen(length)
+	frame := make([]byte, prefix+int(length))
+	frame[0] = version
+	binary.PutUvarint(frame[1:], length)
+	if _, err = io.ReadFull(r, frame[prefix:]); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return frame, nil
+}
+
+// unexpectedEOF turns an io.EOF in the middle of a frame into ErrBinaryTruncated.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrBinaryTruncated
+	}
+	return err
+}
+
+// uvarintLen returns the number of bytes of the uvarint encoding of x.
+func uvarintLen(x uint64) int {
+	return (bits.Len64(x|1) + 6) / 7
+}
+
+// decodeBinaryMetadata decodes the metadata that follows the header read by dec and fills the model.
+func (m *CommonModel) decodeBinaryMetadata(dec *binaryDecoder) error {
+	metadata, err := m.resetMetadata(dec.header.EventCode)
+	if err != nil {
+		return err
+	}
+
+	if err = dec.decodeValue(reflect.ValueOf(metadata).Elem()); err != nil {
+		return withBinaryPath(err, "Metadata")
+	}
+	if dec.pos != len(dec.data) {
+		return ErrBinaryTrailing
+	}
+
+	m.CommonHeader = dec.header
+	m.Metadata = metadata
+	return nil
+}
+
+// --------------------------------------------------
+// Field plans and errors
+// --------------------------------------------------
+
+// binaryField is an exported field of a struct type.
+type binaryField struct {
+	index int
+	name  string
+}
+
+// binaryPlans caches the exported fields of the encoded struct types, in declaration order.
+var binaryPlans sync.Map // key: reflect.Type, value: []binaryField
+
+// binaryFields returns the exported fields of a struct type, in declaration order.
+func binaryFields(t reflect.Type) []binaryField {
+	if fields, isExists := binaryPlans.Load(t); isExists {
+		return fields.([]binaryField)
+	}
+
+	fields := make([]binaryField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.IsExported() {
+			fields = append(fields, binaryField{index: i, name: field.Name})
+		}
+	}
+	actual, _ := binaryPlans.LoadOrStore(t, fields)
+	return actual.([]binaryField)
+}
+
+// binaryFieldError is an error about a single value. Its path is only built when the
+// error unwinds, so that encoding and decoding do not build paths for valid values.
+type binaryFieldError struct {
+	path   []string // innermost element first
+	format string   // takes the path as its first argument
+	args   []any
+}
+
+func newBinaryFieldError(format string, args ...any) error {
+	return &binaryFieldError{format: format, args: args}
+}
+
+func (e *binaryFieldError) Error() string {
+	var path strings.Builder
+	for i := len(e.path) - 1; i >= 0; i-- {
+		if path.Len() > 0 && !strings.HasPrefix(e.path[i], "[") {
+			path.WriteByte('.')
+		}
+		path.WriteString(e.path[i])
+	}
+	return fmt.Sprintf(e.format, append([]any{path.String()}, e.args...)...)
+}
+
+// withBinaryPath prepends a field name or a list index to the path of a binaryFieldError.
+func withBinaryPath(err error, element string) error {
+	var fieldErr *binaryFieldError
+	if errors.As(err, &fieldErr) {
+		fieldErr.path = append(fieldErr.path, element)
+	}
+	return err
+}
+
+// binaryIndex returns the path element of a list index.
+func binaryIndex(i int) string {
+	return "[" + strconv.Itoa(i) + "]"
+}
+
+// --------------------------------------------------
+// Encoder
+// --------------------------------------------------
+
+// binaryInternScan is the number of strings up to which intern scans the table instead of using a map.
+const binaryInternScan = 32
+
+// maxPooledBuffer is the largest buffer kept by pooled encoders and decoders.
+const maxPooledBuffer = 64 << 10
+
+var binaryEncoders = sync.Pool{New: func() any { return &binaryEncoder{} }}
+
+// binaryEncoder writes values into a body and interns their strings.
+type binaryEncoder struct {
+	body    []byte
+	strings []string
+	index   map[string]uint64 // only used beyond binaryInternScan strings
+}
+
+// release resets the encoder and puts it back into its pool.
+func (e *binaryEncoder) release() {
+	if cap(e.body) > maxPooledBuffer {
+		return
+	}
+	clear(e.strings)
+	e.body, e.strings, e.index = e.body[:0], e.strings[:0], nil
+	binaryEncoders.Put(e)
+}
+
+// bytes returns the framed event: version, length, string table and body.
+func (e *binaryEncoder) bytes() []byte {
+	tableLen := uvarintLen(uint64(len(e.strings)))
+	for _, s := range e.strings {
+		tableLen += uvarintLen(uint64(len(s))) + len(s)
+	}
+	length := uint64(tableLen + len(e.body))
+
+	out := make([]byte, 0, 1+uvarintLen(length)+int(length))
+	out = append(out, BINARY_FORMAT_VERSION)
+	out = binary.AppendUvarint(out, length)
+	out = binary.AppendUvarint(out, uint64(len(e.strings)))
+	for _, s := range e.strings {
+		out = binary.AppendUvarint(out, uint64(len(s)))
+		out = append(out, s...)
+	}
+	return append(out, e.body...)
+}
+
+// intern returns the index of s in the string table, adding it if needed.
+// Events have few strings, so the table is scanned until it grows beyond binaryInternScan.
+func (e *binaryEncoder) intern(s string) uint64 {
+	if e.index != nil {
+		if i, isExists := e.index[s]; isExists {
+			return i
+		}
+	} else {
+		for i, interned := range e.strings {
+			if interned == s {
+				return uint64(i)
+			}
+		}
+	}
+
+	i := uint64(len(e.strings))
+	e.strings = append(e.strings, s)
+	switch {
+	case e.index != nil:
+		e.index[s] = i
+	case len(e.strings) > binaryInternScan:
+		e.index = make(map[string]uint64, 2*len(e.strings))
+		for j, interned := range e.strings {
+			e.index[interned] = uint64(j)
+		}
+	}
+	return i
+}
+
+// encodeValue appends the kind and the encoding of v.
+func (e *binaryEncoder) encodeValue(v reflect.Value) error {
+	if !v.IsValid() {
+		e.body = append(e.body, byte(binaryNil))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			e.body = append(e.body, byte(binaryNil))
+			return nil
+		}
+		return e.encodeValue(v.Elem())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.body = append(e.body, byte(binaryInt))
+		e.body = binary.AppendVarint(e.body, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		e.body = append(e.body, byte(binaryUint))
+		e.body = binary.AppendUvarint(e.body, v.Uint())
+	case reflect.String:
+		e.body = append(e.body, byte(binaryString))
+		e.body = binary.AppendUvarint(e.body, e.intern(v.String()))
+	case reflect.Bool:
+		e.body = append(e.body, byte(binaryBool))
+		if v.Bool() {
+			e.body = append(e.body, 1)
+		} else {
+			e.body = append(e.body, 0)
+		}
+	case reflect.Float32, reflect.Float64:
+		e.body = append(e.body, byte(binaryFloat))
+		e.body = binary.LittleEndian.AppendUint64(e.body, math.Float64bits(v.Float()))
+	case reflect.Slice, reflect.Array:
+		e.body = append(e.body, byte(binaryList))
+		e.body = binary.AppendUvarint(e.body, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := e.encodeValue(v.Index(i)); err != nil {
+				return withBinaryPath(err, binaryIndex(i))
+			}
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			e.encodeTime(timeOf(v))
+			return nil
+		}
+		fields := binaryFields(v.Type())
+		e.body = append(e.body, byte(binaryStruct))
+		e.body = binary.AppendUvarint(e.body, uint64(len(fields)))
+		for _, field := range fields {
+			if err := e.encodeValue(v.Field(field.index)); err != nil {
+				return withBinaryPath(err, field.name)
+			}
+		}
+	default:
+		return newBinaryFieldError(ErrBinaryUnsupported, v.Type())
+	}
+	return nil
+}
+
+// timeOf returns the time.Time held by v, without copying it into an interface when v is addressable.
+func timeOf(v reflect.Value) time.Time {
+	if v.CanAddr() {
+		return *v.Addr().Interface().(*time.Time)
+	}
+	return v.Interface().(time.Time)
+}
+
+// encodeTime appends a timestamp as nanoseconds and its zone offset in seconds.
+// The zero time is encoded as a single 0 flag byte.
+func (e *binaryEncoder) encodeTime(t time.Time) {
+	e.body = append(e.body, byte(binaryTime))
+	if t.IsZero() {
+		e.body = append(e.body, 0)
+		return
+	}
+	_, offset := t.Zone()
+	e.body = append(e.body, 1)
+	e.body = binary.AppendVarint(e.body, t.UnixNano())
+	e.body = binary.AppendVarint(e.body, int64(offset))
+}
+
+// --------------------------------------------------
+// Decoder
+// --------------------------------------------------
+
+var binaryDecoders = sync.Pool{New: func() any { return &binaryDecoder{} }}
+
+// binaryZones caches the fixed zones of the decoded timestamps.
+var binaryZones sync.Map // key: int64 offset in seconds, value: *time.Location
+
+// binaryDecoder reads values from the body of an encoded event.
+type binaryDecoder struct {
+	data    []byte
+	pos     int
+	strings []string
+	header  CommonHeader
+}
+
+// release resets the decoder and puts it back into its pool.
+func (d *binaryDecoder) release() {
+	if cap(d.strings) > maxPooledBuffer {
+		return
+	}
+	clear(d.strings)
+	d.data, d.pos, d.strings, d.header = nil, 0, d.strings[:0], CommonHeader{}
+	binaryDecoders.Put(d)
+}
+
+// readHeader checks the framing of data, reads its string table and decodes the header.
+func (d *binaryDecoder) readHeader(data []byte) error {
+	if len(data) == 0 {
+		return ErrBinaryTruncated
+	}
+	if data[0] != BINARY_FORMAT_VERSION {
+		return fmt.Errorf(ErrBinaryVersion, data[0])
+	}
+	length, n := binary.Uvarint(data[1:])
+	if n <= 0 {
+		return ErrBinaryTruncated
+	}
+	body := data[1+n:]
+	if uint64(len(body)) < length {
+		return ErrBinaryTruncated
+	}
+	if uint64(len(body)) > length {
+		return ErrBinaryTrailing
+	}
+
+	d.data, d.pos = body, 0
+	if err := d.readStrings(); err != nil {
+		return err
+	}
+	if err := d.decodeValue(reflect.ValueOf(&d.header).Elem()); err != nil {
+		return withBinaryPath(err, "CommonHeader")
+	}
+	return nil
+}
+
+func (d *binaryDecoder) readByte() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, ErrBinaryTruncated
+	}
+	b := d.data[d.pos]
+	d.pos++
+	return b, nil
+}
+
+func (d *binaryDecoder) readUvarint() (uint64, error) {
+	value, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 {
+		return 0, ErrBinaryTruncated
+	}
+	d.pos += n
+	return value, nil
+}
+
+func (d *binaryDecoder) readVarint() (int64, error) {
+	value, n := binary.Varint(d.data[d.pos:])
+	if n <= 0 {
+		return 0, ErrBinaryTruncated
+	}
+	d.pos += n
+	return value, nil
+}
+
+// readStrings reads the interned string table.
+// The table is copied into a single string, and its strings are slices of it.
+func (d *binaryDecoder) readStrings() error {
+	count, err := d.readUvarint()
+	if err != nil {
+		return err
+	}
+	if count > uint64(len(d.data)-d.pos) {
+		return ErrBinaryTruncated
+	}
+
+	// find the end of the table before copying it
+	start := d.pos
+	for i := uint64(0); i < count; i++ {
+		length, err := d.readUvarint()
+		if err != nil {
+			return err
+		}
+		if length > uint64(len(d.data)-d.pos) {
+			return ErrBinaryTruncated
+		}
+		d.pos += int(length)
+	}
+	table := string(d.data[start:d.pos])
+
+	for offset := 0; offset < len(table); {
+		length, n := binary.Uvarint(d.data[start+offset:])
+		offset += n
+		d.strings = append(d.strings, table[offset:offset+int(length)])
+		offset += int(length)
+	}
+	return nil
+}
+
+// expect reads the kind of the next value and checks it against want.
+func (d *binaryDecoder) expect(want binaryKind) error {
+	b, err := d.readByte()
+	if err != nil {
+		return err
+	}
+	if got := binaryKind(b); got != want {
+		return newBinaryFieldError(ErrBinaryKind, want, got)
+	}
+	return nil
+}
+
+// decodeValue decodes the next value into v.
+func (d *binaryDecoder) decodeValue(v reflect.Value) error {
+	// a nil value leaves the destination at its zero value
+	if d.pos < len(d.data) && binaryKind(d.data[d.pos]) == binaryNil {
+		d.pos++
+		v.SetZero()
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decodeValue(v.Elem())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if err := d.expect(binaryInt); err != nil {
+			return err
+		}
+		value, err := d.readVarint()
+		if err != nil {
+			return err
+		}
+		if v.OverflowInt(value) {
+			return newBinaryFieldError(ErrBinaryOverflow, value, v.Type())
+		}
+		v.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if err := d.expect(binaryUint); err != nil {
+			return err
+		}
+		value, err := d.readUvarint()
+		if err != nil {
+			return err
+		}
+		if v.OverflowUint(value) {
+			return newBinaryFieldError(ErrBinaryOverflow, value, v.Type())
+		}
+		v.SetUint(value)
+	case reflect.String:
+		if err := d.expect(binaryString); err != nil {
+			return err
+		}
+		index, err := d.readUvarint()
+		if err != nil {
+			return err
+		}
+		if index >= uint64(len(d.strings)) {
+			return newBinaryFieldError(ErrBinaryString, index)
+		}
+		v.SetString(d.strings[index])
+	case reflect.Bool:
+		if err := d.expect(binaryBool); err != nil {
+			return err
+		}
+		b, err := d.readByte()
+		if err != nil {
+			return err
+		}
+		v.SetBool(b != 0)
+	case reflect.Float32, reflect.Float64:
+		if err := d.expect(binaryFloat); err != nil {
+			return err
+		}
+		if len(d.data)-d.pos < 8 {
+			return ErrBinaryTruncated
+		}
+		v.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(d.data[d.pos:])))
+		d.pos += 8
+	case reflect.Slice:
+		if err := d.expect(binaryList); err != nil {
+			return err
+		}
+		length, err := d.readUvarint()
+		if err != nil {
+			return err
+		}
+		if length > uint64(len(d.data)-d.pos) {
+			return ErrBinaryTruncated
+		}
+		v.Set(reflect.MakeSlice(v.Type(), int(length), int(length)))
+		for i := 0; i < int(length); i++ {
+			if err = d.decodeValue(v.Index(i)); err != nil {
+				return withBinaryPath(err, binaryIndex(i))
+			}
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return d.decodeTime(v)
+		}
+		return d.decodeStruct(v)
+	default:
+		return newBinaryFieldError(ErrBinaryUnsupported, v.Type())
+	}
+	return nil
+}
+
+// decodeStruct decodes the fields of a struct, skipping the fields it does not know.
+func (d *binaryDecoder) decodeStruct(v reflect.Value) error {
+	if err := d.expect(binaryStruct); err != nil {
+		return err
+	}
+	count, err := d.readUvarint()
+	if err != nil {
+		return err
+	}
+
+	fields := binaryFields(v.Type())
+	for i := uint64(0); i < count; i++ {
+		if i >= uint64(len(fields)) {
+			if err = d.skipValue(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = d.decodeValue(v.Field(fields[i].index)); err != nil {
+			return withBinaryPath(err, fields[i].name)
+		}
+	}
+	return nil
+}
+
+// decodeTime decodes a timestamp into v.
+func (d *binaryDecoder) decodeTime(v reflect.Value) error {
+	if err := d.expect(binaryTime); err != nil {
+		return err
+	}
+	flag, err := d.readByte()
+	if err != nil {
+		return err
+	}
+	if flag == 0 {
+		v.SetZero()
+		return nil
+	}
+	nanos, err := d.readVarint()
+	if err != nil {
+		return err
+	}
+	offset, err := d.readVarint()
+	if err != nil {
+		return err
+	}
+
+	t := time.Unix(0, nanos).In(fixedZone(offset))
+	if v.CanAddr() {
+		*v.Addr().Interface().(*time.Time) = t
+	} else {
+		v.Set(reflect.ValueOf(t))
+	}
+	return nil
+}
+
+// fixedZone returns the zone of a decoded offset in seconds, UTC for 0.
+func fixedZone(offset int64) *time.Location {
+	if offset == 0 {
+		return time.UTC
+	}
+	if location, isExists := binaryZones.Load(offset); isExists {
+		return location.(*time.Location)
+	}
+	location, _ := binaryZones.LoadOrStore(offset, time.FixedZone("", int(offset)))
+	return location.(*time.Location)
+}
+
+// skipValue skips the next value, whatever its kind.
+func (d *binaryDecoder) skipValue() (err error) {
+	b, err := d.readByte()
+	if err != nil {
+		return err
+	}
+
+	switch binaryKind(b) {
+	case binaryNil:
+	case binaryInt:
+		_, err = d.readVarint()
+	case binaryUint, binaryString:
+		_, err = d.readUvarint()
+	case binaryBool:
+		_, err = d.readByte()
+	case binaryFloat:
+		if len(d.data)-d.pos < 8 {
+			return ErrBinaryTruncated
+		}
+		d.pos += 8
+	case binaryTime:
+		var flag byte
+		if flag, err = d.readByte(); err == nil && flag != 0 {
+			if _, err = d.readVarint(); err == nil {
+				_, err = d.readVarint()
+			}
+		}
+	case binaryStruct, binaryList:
+		var count uint64
+		if count, err = d.readUvarint(); err != nil {
+			return err
+		}
+		for i := uint64(0); i < count && err == nil; i++ {
+			err = d.skipValue()
+		}
+	default:
+		return fmt.Errorf(ErrBinaryUnknownKind, b)
+	}
+	return err
+}
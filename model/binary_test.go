@@ -0,0 +1,239 @@
+package commonModel_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	eventModel "github.com/enki-polvo/polvo-logger/model/event"
+	stateConstants "github.com/enki-polvo/polvo-logger/model/state"
+	eventPool "github.com/enki-polvo/polvo-logger/pool"
+)
+
+// binaryTestEvents returns one event of every built-in type.
+func binaryTestEvents() []*commonModel.CommonModel {
+	timestamp := time.Date(2025, 6, 9, 16, 54, 26, 270720921, time.FixedZone("KST", 9*60*60))
+	header := func(code commonModel.EventCode) commonModel.CommonHeader {
+		return commonModel.CommonHeader{
+			EventCode:     code,
+			EventName:     code.String(),
+			Source:        "eBPF",
+			Timestamp:     timestamp,
+			SchemaVersion: commonModel.CURRENT_SCHEMA_VERSION,
+		}
+	}
+
+	return []*commonModel.CommonModel{
+		{CommonHeader: header(commonModel.PROC_CREATE), Metadata: &eventModel.ProcessCreateMetadata{PID: 97469, PPID: 97467, UID: 1000, Username: "shhong", TGID: 97469, Commandline: "sh sed s/-//", ENV: "HOME=/home/shhong", Image: "/usr/bin/sed"}},
+		{CommonHeader: header(commonModel.PROC_TERMINATE), Metadata: &eventModel.ProcessTerminateMetadata{PID: 97469, Ret: -1, UID: 1000, Username: "shhong"}},
+		{CommonHeader: header(commonModel.PROC_BASH_READLINE), Metadata: &eventModel.BashReadlineMetadata{PID: 1234, Commandline: "rm -rf /tmp", UID: 0, Username: "root"}},
+		{CommonHeader: header(commonModel.PROC_SERVICE), Metadata: &eventModel.ServiceMetadata{PID: 1, UID: 0, TTY: "pts/0", Image: "/usr/bin/bash", Commandline: "bash"}},
+		{CommonHeader: header(commonModel.TCP_EVENT), Metadata: &eventModel.TcpMetadata{PID: 1234, Daddr: "10.0.0.1", Dport: 443, Saddr: "10.0.0.2", Sport: 51234, Protocol: 4, Op: stateConstants.TCP_ACCEPT}},
+		{CommonHeader: header(commonModel.FILE_OPEN_EVENT), Metadata: &eventModel.FileOpenMetadata{PID: 8080, FileOpenerUID: 1200, FileOpenerGID: 1000, FileOpenerUsername: "root", FileOwnerUID: 1200, FileOwnerGID: 1000, FileOwnerUsername: "root", Mode: 0444, Fmode: 0100644, FileOpenPurposeOp: stateConstants.FILE_OPEN_TO_WRITE, Inode: 17986650, Size: 1048576, ProcessName: "bash", Path: "/var/log/syslog"}},
+		{CommonHeader: header(commonModel.FILE_RENAME_EVENT), Metadata: &eventModel.FileRenameMetadata{PID: 8080, UID: 1200, GID: 1000, Username: "root", Command: "mv", OldPath: "/var/log/syslog", NewPath: "/var/log/syslog.backup"}},
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	// Every event type decodes from binary into the same JSON form as the original
+	for _, origin := range binaryTestEvents() {
+		data, err := origin.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Failed to marshal %v: %v", origin.EventName, err)
+		}
+
+		decoded := &commonModel.CommonModel{}
+		if err = decoded.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Failed to unmarshal %v: %v", origin.EventName, err)
+		}
+
+		want, _ := json.Marshal(origin)
+		got, _ := json.Marshal(decoded)
+		if !bytes.Equal(want, got) {
+			t.Fatalf("Binary round trip of %v does not match JSON form:\n got: %s\nwant: %s", origin.EventName, got, want)
+		}
+		t.Logf("%v: %d bytes binary, %d bytes JSON", origin.EventName, len(data), len(want))
+	}
+}
+
+func TestBinaryDecodeIntoPool(t *testing.T) {
+	// Binary events decoded through an allocator reuse pool-allocated metadata
+	pool := eventPool.NewEventPool()
+	origin := binaryTestEvents()[4]
+
+	data, err := origin.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	cm, err := commonModel.UnmarshalBinaryFrom(data, pool)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	metadata, ok := cm.Metadata.(*eventModel.TcpMetadata)
+	if !ok || metadata.Op != stateConstants.TCP_ACCEPT {
+		t.Fatalf("Decoded metadata does not match: %+v", cm.Metadata)
+	}
+	if err = pool.Free(cm); err != nil {
+		t.Fatalf("Failed to free event: %v", err)
+	}
+}
+
+func TestBinaryFrames(t *testing.T) {
+	// Length-prefixed frames can be read back one by one from a stream
+	var stream bytes.Buffer
+	events := binaryTestEvents()
+	for _, origin := range events {
+		data, err := origin.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Failed to marshal: %v", err)
+		}
+		stream.Write(data)
+	}
+
+	r := bufio.NewReader(&stream)
+	for _, origin := range events {
+		frame, err := commonModel.ReadBinaryFrame(r)
+		if err != nil {
+			t.Fatalf("Failed to read frame: %v", err)
+		}
+		decoded := &commonModel.CommonModel{}
+		if err = decoded.UnmarshalBinary(frame); err != nil {
+			t.Fatalf("Failed to unmarshal frame: %v", err)
+		}
+		if decoded.EventCode != origin.EventCode {
+			t.Fatalf("Decoded event code does not match: got %v, want %v", decoded.EventCode, origin.EventCode)
+		}
+	}
+}
+
+func TestBinaryInvalidData(t *testing.T) {
+	data, err := binaryTestEvents()[0].MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	decoded := &commonModel.CommonModel{}
+	if err = decoded.UnmarshalBinary(data[:len(data)-3]); err == nil {
+		t.Fatal("Expected an error due to truncated data, but got none")
+	}
+
+	data[0] = 99
+	if err = decoded.UnmarshalBinary(data); err == nil {
+		t.Fatal("Expected an error due to unsupported version, but got none")
+	}
+}
+
+func TestBinaryFrameSize(t *testing.T) {
+	// Frames longer than the maximum size are rejected before they are read
+	data, err := binaryTestEvents()[0].MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	if _, err = commonModel.ReadBinaryFrameSize(bufio.NewReader(bytes.NewReader(data)), 16); err == nil {
+		t.Fatal("Expected an error due to oversized frame, but got none")
+	}
+	frame, err := commonModel.ReadBinaryFrameSize(bufio.NewReader(bytes.NewReader(data)), len(data))
+	if err != nil {
+		t.Fatalf("Failed to read frame: %v", err)
+	}
+	if !bytes.Equal(frame, data) {
+		t.Fatalf("Read frame does not match: got %v, want %v", frame, data)
+	}
+
+	// a corrupt length prefix does not allocate the length it claims
+	corrupt := []byte{commonModel.BINARY_FORMAT_VERSION, 0xff, 0xff, 0xff, 0xff, 0x07}
+	if _, err = commonModel.ReadBinaryFrame(bufio.NewReader(bytes.NewReader(corrupt))); err == nil {
+		t.Fatal("Expected an error due to oversized frame, but got none")
+	}
+}
+
+func TestBinaryFieldError(t *testing.T) {
+	// Errors name the path of the failing field
+	origin := binaryTestEvents()[0]
+	origin.Metadata = &struct{ Values []map[string]int }{Values: []map[string]int{{}}}
+
+	_, err := origin.MarshalBinary()
+	if err == nil {
+		t.Fatal("Expected an error due to unsupported type, but got none")
+	}
+	if want := "binary field 'Metadata.Values[0]'"; !strings.HasPrefix(err.Error(), want) {
+		t.Fatalf("Error does not name the field: got %q, want prefix %q", err, want)
+	}
+}
+
+func TestBinaryDecodeReusesMetadata(t *testing.T) {
+	// Decoding into an event whose metadata has the registered type allocates no new metadata
+	data, err := binaryTestEvents()[4].MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	cm := &commonModel.CommonModel{}
+	decode := func(reuse bool) float64 {
+		return testing.AllocsPerRun(100, func() {
+			if !reuse {
+				cm.Metadata = nil
+			}
+			if err := cm.UnmarshalBinary(data); err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+		})
+	}
+	if reused, fresh := decode(true), decode(false); reused >= fresh {
+		t.Fatalf("Reused metadata is allocated anyway: %v allocations, %v without metadata", reused, fresh)
+	}
+}
+
+// BenchmarkFileOpenEncoding compares the binary and the JSON encoding of a FileOpenEvent.
+func BenchmarkFileOpenEncoding(b *testing.B) {
+	origin := binaryTestEvents()[5]
+	binaryData, err := origin.MarshalBinary()
+	if err != nil {
+		b.Fatalf("Failed to marshal: %v", err)
+	}
+	jsonData, err := json.Marshal(origin)
+	if err != nil {
+		b.Fatalf("Failed to marshal JSON: %v", err)
+	}
+
+	b.Run("MarshalBinary", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := origin.MarshalBinary(); err != nil {
+				b.Fatalf("Failed to marshal: %v", err)
+			}
+		}
+	})
+	b.Run("MarshalJSON", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := json.Marshal(origin); err != nil {
+				b.Fatalf("Failed to marshal JSON: %v", err)
+			}
+		}
+	})
+	b.Run("UnmarshalBinary", func(b *testing.B) {
+		b.ReportAllocs()
+		decoded := &commonModel.CommonModel{}
+		for i := 0; i < b.N; i++ {
+			if err := decoded.UnmarshalBinary(binaryData); err != nil {
+				b.Fatalf("Failed to unmarshal: %v", err)
+			}
+		}
+	})
+	b.Run("UnmarshalJSON", func(b *testing.B) {
+		b.ReportAllocs()
+		decoded := &commonModel.CommonModel{}
+		for i := 0; i < b.N; i++ {
+			if err := json.Unmarshal(jsonData, decoded); err != nil {
+				b.Fatalf("Failed to unmarshal JSON: %v", err)
+			}
+		}
+	})
+}
@@ -3,6 +3,8 @@ package commonModel
 
 import (
 	"time"
+
+	stateConstants "github.com/enki-polvo/polvo-logger/model/state"
 )
 
 // EventCode defines the event code type.
@@ -37,24 +39,86 @@ func (e EventCode) String() string {
 	case FILE_RENAME_EVENT:
 		return "FileRenameEvent"
 	default:
-		return ""
+		return registeredName(e)
+	}
+}
+
+var eventCodes = []EventCode{PROC_CREATE, PROC_TERMINATE, PROC_BASH_READLINE, PROC_SERVICE, TCP_EVENT, FILE_OPEN_EVENT, FILE_RENAME_EVENT}
+
+// ParseEventCode parses an EventCode from its name (e.g. "ProcessTerminate") or its number.
+// Names of registered third-party event types are accepted as well.
+func ParseEventCode(text string) (EventCode, error) {
+	code, isExists := lookupEventCode(text)
+	if !isExists {
+		return 0, parseEventCodeError(text)
 	}
+	return code, nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e EventCode) MarshalText() ([]byte, error) {
+	return stateConstants.MarshalEnumText(e)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts both names and numbers.
+func (e *EventCode) UnmarshalText(text []byte) error {
+	value, err := ParseEventCode(string(text))
+	if err != nil {
+		return err
+	}
+	*e = value
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e EventCode) MarshalJSON() ([]byte, error) {
+	return stateConstants.MarshalEnumJSON(e)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both strings and numbers.
+func (e *EventCode) UnmarshalJSON(data []byte) error {
+	return stateConstants.UnmarshalEnumJSON(data, e)
 }
 
 // CommonHeader defines the common header structure for all events.
+// New fields are appended at the end, so that older binary encodings keep decoding.
 type CommonHeader struct {
-	EventCode EventCode `json:"EventCode"` // example: 1
-	EventName string    `json:"EventName"` // example: "ProcessCreate"
-	Source    string    `json:"Source"`    // example: "eBPF"
-	Timestamp time.Time `json:"Timestamp"` // example: "2023-10-01T12:00:00Z"
+	EventCode      EventCode   `json:"EventCode"`                // example: 1
+	EventName      string      `json:"EventName"`                // example: "ProcessCreate"
+	Source         string      `json:"Source"`                   // example: "eBPF"
+	Timestamp      time.Time   `json:"Timestamp"`                // example: "2023-10-01T12:00:00Z"
+	SchemaVersion  uint32      `json:"SchemaVersion"`            // example: 1
+	HostID         string      `json:"HostID,omitempty"`         // example: "4c4c4544004b4a10804cb7c04f4e3132"
+	Hostname       string      `json:"Hostname,omitempty"`       // example: "web-01"
+	BootID         string      `json:"BootID,omitempty"`         // example: "9a1b0c6e-2f35-4a8e-bb0c-6b1f3a0d7e21"
+	AgentVersion   string      `json:"AgentVersion,omitempty"`   // example: "1.4.0"
+	EventID        string      `json:"EventID,omitempty"`        // example: "01JXB4T5K3Z8Q2W9E7R6Y5V4N3"
+	Seq            uint64      `json:"Seq,omitempty"`            // example: 42
+	MatchedRuleIDs RuleMatches `json:"MatchedRuleIDs,omitempty"` // example: [{"RuleID": "5f0e6d2a-proc-creation-lnx-susp-curl", "Severity": "HIGH", "MatchedAt": "2025-01-01T00:00:00Z"}]
 }
 
 // CommonModel defines the common structure for all events and entity.
+// Its JSON and binary decoders only know the registered event types: programs decoding
+// built-in events must import EVENT_MODEL_PACKAGE, e.g. as _ "github.com/enki-polvo/polvo-logger/model/event".
 type CommonModel struct {
 	CommonHeader
 	Metadata any `json:"Metadata"`
 }
 
+// Reset zeroes the header and the metadata of the event, keeping the EventCode with its
+// EventName and the current SchemaVersion, as eventPool.Pool does before reusing it.
+// The metadata structure is reused if it has the type registered for the EventCode.
+func (m *CommonModel) Reset() error {
+	code := m.EventCode
+	metadata, err := m.resetMetadata(code)
+	if err != nil {
+		return err
+	}
+	m.CommonHeader = CommonHeader{EventCode: code, EventName: code.String(), SchemaVersion: CURRENT_SCHEMA_VERSION}
+	m.Metadata = metadata
+	return nil
+}
+
 // CommonModelWrapper is a wrapper for CommonModel that includes a Metadata field as map.
 // This is useful for decoding purposes, where the Metadata can be a map of any type.
 type CommonModelWrapper struct {
@@ -1,11 +1,14 @@
 package commonModel_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"testing"
 
 	commonModel "github.com/enki-polvo/polvo-logger/model"
 	eventModel "github.com/enki-polvo/polvo-logger/model/event"
+	stateConstants "github.com/enki-polvo/polvo-logger/model/state"
+	eventPool "github.com/enki-polvo/polvo-logger/pool"
 )
 
 func TestDecodeMetadata(t *testing.T) {
@@ -92,3 +95,141 @@ func TestDecodeMetadataWithDifferentDataType(t *testing.T) {
 	}
 	t.Logf("Decoded Metadata with different type: %+v", dest.Metadata)
 }
+
+func TestUnmarshalCommonModel(t *testing.T) {
+	// CommonModel decodes its Metadata into the structure registered for the EventCode
+	data := `{"EventCode":4,"EventName":"TcpEvent","Source":"eBPF","Timestamp":"2025-06-09T16:54:26.270720921+09:00","Metadata":{"PID":1234,"Daddr":"10.0.0.1","Dport":443,"Saddr":"10.0.0.2","Sport":51234,"Protocol":4,"Op":1}}`
+
+	cm := &commonModel.CommonModel{}
+	err := json.Unmarshal([]byte(data), cm)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+
+	metadata, ok := cm.Metadata.(*eventModel.TcpMetadata)
+	if !ok {
+		t.Fatalf("Decoded metadata is not of type *TcpMetadata: %T", cm.Metadata)
+	}
+	if metadata.Dport != 443 || cm.Source != "eBPF" {
+		t.Fatalf("Decoded CommonModel does not match: %+v, metadata: %+v", cm, metadata)
+	}
+}
+
+func TestUnmarshalCommonModelWithUnknownCode(t *testing.T) {
+	data := `{"EventCode":999,"EventName":"Unknown","Source":"eBPF","Metadata":{}}`
+
+	cm := &commonModel.CommonModel{}
+	err := json.Unmarshal([]byte(data), cm)
+	if err == nil {
+		t.Fatal("Expected an error due to unknown event code, but got none")
+	}
+	t.Logf("Expected error occurred: %v", err)
+}
+
+func TestUnmarshalCommonModelWithPool(t *testing.T) {
+	// CommonModel decoded through an allocator reuses the pool-allocated metadata
+	pool := eventPool.NewEventPool()
+	first := `{"EventCode":0,"EventName":"ProcessCreate","Source":"eBPF","Metadata":{"PID":1,"Image":"/usr/bin/sed"}}`
+	second := `{"EventCode":0,"EventName":"ProcessCreate","Source":"eBPF","Metadata":{"PID":2}}`
+
+	cm, err := commonModel.Unmarshal([]byte(first), pool)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	if err = pool.Free(cm); err != nil {
+		t.Fatalf("Failed to free event: %v", err)
+	}
+
+	cm, err = commonModel.Unmarshal([]byte(second), pool)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	metadata, ok := cm.Metadata.(*eventModel.ProcessCreateMetadata)
+	if !ok {
+		t.Fatalf("Decoded metadata is not of type *ProcessCreateMetadata: %T", cm.Metadata)
+	}
+	// fields of a previously freed event must not leak into the new one
+	if metadata.PID != 2 || metadata.Image != "" {
+		t.Fatalf("Decoded metadata does not match: %+v", metadata)
+	}
+}
+
+func TestEventCodeNameEncoding(t *testing.T) {
+	// Events written with names decode into the same values as integer-encoded ones
+	origin := &commonModel.CommonModel{
+		CommonHeader: commonModel.CommonHeader{EventCode: commonModel.TCP_EVENT, EventName: commonModel.TCP_EVENT.String(), Source: "eBPF"},
+		Metadata:     &eventModel.TcpMetadata{PID: 1234, Op: stateConstants.TCP_DISCONNECT},
+	}
+	b, err := json.Marshal(origin)
+	if err != nil {
+		t.Fatalf("Failed to marshal JSON: %v", err)
+	}
+	t.Logf("Encoded CommonModel: %s", b)
+	if !bytes.Contains(b, []byte(`"EventCode":"TcpEvent"`)) || !bytes.Contains(b, []byte(`"Op":"TCP_DISCONNECT"`)) {
+		t.Fatalf("Enums were not encoded as names: %s", b)
+	}
+
+	// decode data into CommonModelWrapper and metadata
+	cmw := &commonModel.CommonModelWrapper{}
+	if err = json.Unmarshal(b, cmw); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	if cmw.EventCode != commonModel.TCP_EVENT {
+		t.Fatalf("Decoded event code does not match: got %v, want %v", cmw.EventCode, commonModel.TCP_EVENT)
+	}
+	dest := eventModel.TcpMetadata{}
+	if err = eventModel.DecodeMetadataAs(cmw.Metadata, &dest); err != nil {
+		t.Fatalf("Failed to decode metadata: %v", err)
+	}
+	if dest.Op != stateConstants.TCP_DISCONNECT {
+		t.Fatalf("Decoded op does not match: got %v, want %v", dest.Op, stateConstants.TCP_DISCONNECT)
+	}
+}
+
+// Test that parsing an EventCode looks the registry up without allocating
+func TestParseEventCodeAllocations(t *testing.T) {
+	for _, text := range []string{"TcpEvent", "4"} {
+		allocs := testing.AllocsPerRun(100, func() {
+			if code, err := commonModel.ParseEventCode(text); err != nil || code != commonModel.TCP_EVENT {
+				t.Fatalf("Failed to parse event code %q: %v, %v", text, code, err)
+			}
+		})
+		if allocs != 0 {
+			t.Fatalf("Parsing %q allocates: %v allocations per call", text, allocs)
+		}
+	}
+}
+
+// countingAllocator counts the events given back to its pool.
+type countingAllocator struct {
+	eventPool.Pool
+	freed int
+}
+
+func (a *countingAllocator) Free(event any) error {
+	a.freed++
+	return a.Pool.Free(event)
+}
+
+// Test that the decoders give the allocated event back when decoding fails
+func TestUnmarshalFreesOnError(t *testing.T) {
+	allocator := &countingAllocator{Pool: eventPool.NewEventPool()}
+
+	_, err := commonModel.Unmarshal([]byte(`{"EventCode":4,"Source":"eBPF","Metadata":{"PID":"not a number"}}`), allocator)
+	if err == nil || allocator.freed != 1 {
+		t.Fatalf("Expected the JSON event to be freed on error: %v, %d frees", err, allocator.freed)
+	}
+
+	malformed := &commonModel.CommonModel{
+		CommonHeader: commonModel.CommonHeader{EventCode: commonModel.TCP_EVENT, EventName: commonModel.TCP_EVENT.String()},
+		Metadata:     &struct{ PID string }{PID: "not a number"},
+	}
+	data, err := malformed.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	_, err = commonModel.UnmarshalBinaryFrom(data, allocator)
+	if err == nil || allocator.freed != 2 {
+		t.Fatalf("Expected the binary event to be freed on error: %v, %d frees", err, allocator.freed)
+	}
+}
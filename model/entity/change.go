@@ -0,0 +1,145 @@
+// model/entity/change.go
+package entityModel
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	ErrEntityTypeMismatch = "cannot diff %T against %T"
+	ErrUnknownEntityType  = "unknown entity type: %d"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// FieldChange is the old and new value of a changed entity field.
+type FieldChange struct {
+	Field string `json:"Field"` // JSON name of the field, e.g. "NumReadOps"
+	Old   any    `json:"Old"`
+	New   any    `json:"New"`
+}
+
+// ChangeRecord lists the fields of an entity changed by an event,
+// so consumers can apply incremental updates instead of full upserts.
+type ChangeRecord struct {
+	EntityType EntityType    `json:"EntityType"`        // example: 2
+	EntityID   string        `json:"EntityID"`          // example: "17986650:/var/log/syslog"
+	Timestamp  time.Time     `json:"Timestamp"`         // example: "2025-01-01T00:00:00Z"
+	EventID    string        `json:"EventID,omitempty"` // example: "01JXB4T5K3Z8Q2W9E7R6Y5V4N3" (the event causing the change)
+	Changes    []FieldChange `json:"Changes"`
+}
+
+// Diff returns the fields that differ between two versions of the same entity, in field order.
+// Fields of embedded structures, like CommonEntityModel, are compared as fields of the entity.
+func Diff(old, new Entity) ([]FieldChange, error) {
+	oldValue, newValue := reflect.ValueOf(old), reflect.ValueOf(new)
+	if old == nil || new == nil || oldValue.Type() != newValue.Type() {
+		return nil, fmt.Errorf(ErrEntityTypeMismatch, new, old)
+	}
+	return diffStruct(oldValue.Elem(), newValue.Elem(), nil), nil
+}
+
+// diffStruct appends the changed fields of two values of the same struct type to changes.
+func diffStruct(old, new reflect.Value, changes []FieldChange) []FieldChange {
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			changes = diffStruct(old.Field(i), new.Field(i), changes)
+			continue
+		}
+
+		oldField, newField := old.Field(i).Interface(), new.Field(i).Interface()
+		if field.Type == timeType {
+			if oldField.(time.Time).Equal(newField.(time.Time)) {
+				continue
+			}
+		} else if reflect.DeepEqual(oldField, newField) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: jsonName(field), Old: oldField, New: newField})
+	}
+	return changes
+}
+
+// jsonName returns the name of a struct field in JSON.
+func jsonName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// NewChangeRecord returns the change record of an entity updated from old to new by the event with the given ID.
+// It returns nil if no field changed.
+func NewChangeRecord(old, new Entity, timestamp time.Time, eventID string) (*ChangeRecord, error) {
+	changes, err := Diff(old, new)
+	if err != nil || len(changes) == 0 {
+		return nil, err
+	}
+	return &ChangeRecord{
+		EntityType: new.Common().EntityType,
+		EntityID:   new.EntityID(),
+		Timestamp:  timestamp,
+		EventID:    eventID,
+		Changes:    changes,
+	}, nil
+}
+
+// ExportRecord is a line of an entity export: either a whole entity or a change record.
+// Both kinds can be written to the same stream and told apart when read back.
+type ExportRecord struct {
+	Entity Entity        `json:"Entity,omitempty"`
+	Change *ChangeRecord `json:"Change,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// The Entity is decoded into the model structure of its EntityType.
+func (r *ExportRecord) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Entity json.RawMessage `json:"Entity"`
+		Change *ChangeRecord   `json:"Change"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	r.Entity, r.Change = nil, raw.Change
+	if len(raw.Entity) == 0 || string(raw.Entity) == "null" {
+		return nil
+	}
+
+	var common CommonEntityModel
+	if err := json.Unmarshal(raw.Entity, &common); err != nil {
+		return err
+	}
+	entity, err := NewEntity(common.EntityType)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(raw.Entity, entity); err != nil {
+		return err
+	}
+	r.Entity = entity
+	return nil
+}
+
+// NewEntity returns a new empty entity model of the given EntityType.
+func NewEntity(entityType EntityType) (Entity, error) {
+	switch entityType {
+	case PROCESS_ENTITY:
+		return &ProcessEntityModel{}, nil
+	case NETWORK_ENTITY:
+		return &NetworkEntityModel{}, nil
+	case FILE_ENTITY:
+		return &FileEntityModel{}, nil
+	default:
+		return nil, fmt.Errorf(ErrUnknownEntityType, entityType)
+	}
+}
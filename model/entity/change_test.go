@@ -0,0 +1,88 @@
+package entityModel_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	entityModel "github.com/enki-polvo/polvo-logger/model/entity"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+// Test the field-level diff of two versions of an entity
+func TestNewChangeRecord(t *testing.T) {
+	startTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := &entityModel.ProcessEntityModel{
+		CommonEntityModel: entityModel.CommonEntityModel{EntityType: entityModel.PROCESS_ENTITY, State: state.CREATED},
+		ProcessKey:        "100@1735689600000",
+		PID:               100,
+		Image:             "/usr/bin/bash",
+		StartTime:         startTime,
+	}
+	new := old.Clone().(*entityModel.ProcessEntityModel)
+	new.State = state.MODIFIED
+	new.Image = "/usr/bin/cat"
+	// the same instant in another location is not a change
+	new.StartTime = startTime.In(time.FixedZone("KST", 9*60*60))
+
+	record, err := entityModel.NewChangeRecord(old, new, startTime.Add(time.Second), "01JXB4T5K3Z8Q2W9E7R6Y5V4N3")
+	if err != nil {
+		t.Fatalf("Failed to create change record: %v", err)
+	}
+	if record.EntityID != "100@1735689600000" || len(record.Changes) != 2 {
+		t.Fatalf("Change record does not match: %+v", record)
+	}
+	if change := record.Changes[0]; change.Field != "State" || change.Old != state.CREATED || change.New != state.MODIFIED {
+		t.Fatalf("State change does not match: %+v", change)
+	}
+	if change := record.Changes[1]; change.Field != "Image" || change.New != "/usr/bin/cat" {
+		t.Fatalf("Image change does not match: %+v", change)
+	}
+
+	if record, err = entityModel.NewChangeRecord(old, old.Clone(), startTime, ""); err != nil || record != nil {
+		t.Fatalf("Unchanged entity has a change record: %+v, %v", record, err)
+	}
+	if _, err = entityModel.NewChangeRecord(old, &entityModel.FileEntityModel{}, startTime, ""); err == nil {
+		t.Fatal("Expected an error diffing entities of different types, but got none")
+	}
+}
+
+// Test that entities and change records written to the same stream are read back
+func TestExportRecordRoundTrip(t *testing.T) {
+	file := &entityModel.FileEntityModel{
+		CommonEntityModel: entityModel.CommonEntityModel{EntityType: entityModel.FILE_ENTITY, State: state.MODIFIED},
+		Inode:             42,
+		Path:              "/etc/passwd",
+		NumReadOps:        3,
+	}
+	change := &entityModel.ChangeRecord{
+		EntityType: entityModel.FILE_ENTITY,
+		EntityID:   file.EntityID(),
+		Changes:    []entityModel.FieldChange{{Field: "NumReadOps", Old: 2, New: 3}},
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, record := range []entityModel.ExportRecord{{Entity: file}, {Change: change}} {
+		if err := encoder.Encode(record); err != nil {
+			t.Fatalf("Failed to encode export record: %v", err)
+		}
+	}
+
+	decoder := json.NewDecoder(&buf)
+	var records [2]entityModel.ExportRecord
+	for i := range records {
+		if err := decoder.Decode(&records[i]); err != nil {
+			t.Fatalf("Failed to decode export record: %v", err)
+		}
+	}
+
+	decoded, ok := records[0].Entity.(*entityModel.FileEntityModel)
+	if !ok || decoded.EntityID() != file.EntityID() || decoded.NumReadOps != 3 {
+		t.Fatalf("Decoded entity does not match: %+v", records[0].Entity)
+	}
+	if records[1].Entity != nil || records[1].Change == nil || records[1].Change.Changes[0].Field != "NumReadOps" {
+		t.Fatalf("Decoded change record does not match: %+v", records[1])
+	}
+}
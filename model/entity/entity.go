@@ -0,0 +1,97 @@
+// model/entity/entity.go
+package entityModel
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+)
+
+// Entity is implemented by every entity model.
+type Entity interface {
+	// EntityID returns the identifier of the entity, unique within its EntityType.
+	EntityID() string
+	// Common returns the common fields of the entity.
+	Common() *CommonEntityModel
+	// Terminated reports whether the entity reached the end of its lifecycle.
+	Terminated() bool
+	// Clone returns a deep copy of the entity.
+	Clone() Entity
+}
+
+// Common returns the common fields of the entity.
+func (c *CommonEntityModel) Common() *CommonEntityModel {
+	return c
+}
+
+// EntityID returns the ProcessKey of the process, derived from its PID and StartTime if it is not set.
+func (p *ProcessEntityModel) EntityID() string {
+	if p.ProcessKey == "" {
+		return string(commonModel.NewProcessKey(p.PID, p.StartTime))
+	}
+	return string(p.ProcessKey)
+}
+
+// Exited reports whether the process has terminated.
+func (p *ProcessEntityModel) Exited() bool {
+	return !p.ExitTime.IsZero()
+}
+
+// Terminated reports whether the process has terminated.
+func (p *ProcessEntityModel) Terminated() bool {
+	return p.Exited()
+}
+
+// Clone returns a deep copy of the process.
+func (p *ProcessEntityModel) Clone() Entity {
+	clone := *p
+	clone.MatchedRuleIDs = p.MatchedRuleIDs.Clone()
+	return &clone
+}
+
+// EntityID returns the PID, the protocol and the endpoints of the connection,
+// e.g. "1234/4/10.0.0.1:51234-10.0.0.2:80".
+func (n *NetworkEntityModel) EntityID() string {
+	return fmt.Sprintf("%d/%d/%s-%s", n.PID, n.Protocol,
+		net.JoinHostPort(n.Saddr, strconv.FormatInt(n.Sport, 10)),
+		net.JoinHostPort(n.Daddr, strconv.FormatInt(n.Dport, 10)))
+}
+
+// Closed reports whether the connection has been disconnected.
+func (n *NetworkEntityModel) Closed() bool {
+	return !n.EndTime.IsZero()
+}
+
+// Terminated reports whether the connection has been disconnected.
+func (n *NetworkEntityModel) Terminated() bool {
+	return n.Closed()
+}
+
+// Clone returns a deep copy of the connection.
+func (n *NetworkEntityModel) Clone() Entity {
+	clone := *n
+	clone.MatchedRuleIDs = n.MatchedRuleIDs.Clone()
+	return &clone
+}
+
+// EntityID returns the inode and the path of the file, e.g. "17986650:/var/log/syslog".
+// A rename changes the EntityID of a file.
+func (f *FileEntityModel) EntityID() string {
+	return strconv.FormatInt(f.Inode, 10) + ":" + f.Path
+}
+
+// Terminated reports false: files have no lifecycle end.
+func (f *FileEntityModel) Terminated() bool {
+	return false
+}
+
+// Clone returns a deep copy of the file.
+func (f *FileEntityModel) Clone() Entity {
+	clone := *f
+	clone.MatchedRuleIDs = f.MatchedRuleIDs.Clone()
+	clone.RenameHistory = slices.Clone(f.RenameHistory)
+	return &clone
+}
@@ -2,6 +2,9 @@
 package entityModel
 
 import (
+	"time"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
 	state "github.com/enki-polvo/polvo-logger/model/state"
 )
 
@@ -27,34 +30,160 @@ func (e EntityType) String() string {
 	}
 }
 
+var entityTypes = []EntityType{PROCESS_ENTITY, NETWORK_ENTITY, FILE_ENTITY}
+
+// ParseEntityType parses an EntityType from its name (e.g. "NETWORK") or its number.
+func ParseEntityType(text string) (EntityType, error) {
+	return state.ParseEnum(text, entityTypes)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e EntityType) MarshalText() ([]byte, error) {
+	return state.MarshalEnumText(e)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts both names and numbers.
+func (e *EntityType) UnmarshalText(text []byte) error {
+	value, err := ParseEntityType(string(text))
+	if err != nil {
+		return err
+	}
+	*e = value
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e EntityType) MarshalJSON() ([]byte, error) {
+	return state.MarshalEnumJSON(e)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both strings and numbers.
+func (e *EntityType) UnmarshalJSON(data []byte) error {
+	return state.UnmarshalEnumJSON(data, e)
+}
+
+// Direction defines the direction of a network connection.
+type Direction int
+
+const (
+	DIRECTION_UNKNOWN Direction = iota
+	DIRECTION_OUTBOUND
+	DIRECTION_INBOUND
+)
+
+func (d Direction) String() string {
+	switch d {
+	case DIRECTION_UNKNOWN:
+		return "UNKNOWN"
+	case DIRECTION_OUTBOUND:
+		return "OUTBOUND"
+	case DIRECTION_INBOUND:
+		return "INBOUND"
+	default:
+		return ""
+	}
+}
+
+var directions = []Direction{DIRECTION_UNKNOWN, DIRECTION_OUTBOUND, DIRECTION_INBOUND}
+
+// ParseDirection parses a Direction from its name (e.g. "INBOUND") or its number.
+func ParseDirection(text string) (Direction, error) {
+	return state.ParseEnum(text, directions)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Direction) MarshalText() ([]byte, error) {
+	return state.MarshalEnumText(d)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts both names and numbers.
+func (d *Direction) UnmarshalText(text []byte) error {
+	value, err := ParseDirection(string(text))
+	if err != nil {
+		return err
+	}
+	*d = value
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Direction) MarshalJSON() ([]byte, error) {
+	return state.MarshalEnumJSON(d)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both strings and numbers.
+func (d *Direction) UnmarshalJSON(data []byte) error {
+	return state.UnmarshalEnumJSON(data, d)
+}
+
 // CommonEntityModel defines the structure for all entity types.
 type CommonEntityModel struct {
-	EntityType     EntityType  `json:"EntityType"`               // example: 0
-	State          state.State `json:"State"`                    // example: 0
-	MatchedRuleIDs string      `json:"MatchedRuleIDs,omitempty"` // example: "rule1"
+	EntityType     EntityType              `json:"EntityType"`               // example: 0
+	State          state.State             `json:"State"`                    // example: 0
+	MatchedRuleIDs commonModel.RuleMatches `json:"MatchedRuleIDs,omitempty"` // legacy payloads may hold a string of rule IDs, e.g. "rule1,rule2"
 }
 
 // ProcessEntityModel defines the structure for process entities.
+// A process is alive until its ExitTime is set. Its ProcessKey tells apart the processes that reused a PID.
 type ProcessEntityModel struct {
 	CommonEntityModel
+	ProcessKey  commonModel.ProcessKey `json:"ProcessKey"`  // example: "1234@1735689605000"
+	PID         int64                  `json:"PID"`         // example: 1234
+	PPID        int64                  `json:"PPID"`        // example: 4
+	TGID        int64                  `json:"TGID"`        // example: 1234
+	UID         int64                  `json:"UID"`         // example: 1000
+	Image       string                 `json:"Image"`       // example: "/usr/bin/bash"
+	Commandline string                 `json:"Commandline"` // example: "bash rm -rf /tmp"
+	StartTime   time.Time              `json:"StartTime"`   // example: "2025-01-01T00:00:00Z"
+	ExitTime    time.Time              `json:"ExitTime"`    // example: "2025-01-01T00:00:05Z"
+	ExitCode    int64                  `json:"ExitCode"`    // example: 0
 }
 
 // NetworkEntityModel defines the structure for network entities.
+// A connection is open until its EndTime is set. The counters are only filled in
+// when events reporting the traffic of the connection are available.
 type NetworkEntityModel struct {
 	CommonEntityModel
-	// TODO: These fields are placeholders for tracking network activity metrics.
-	// NumRecvOps   int64 `json:"NumRecvOps"`   // example: 100 (Number of Receive operations)
-	// NumSentOps   int64 `json:"NumSentOps"`   // example: 100 (Number of Send operations)
-	// NumRecvBytes int64 `json:"NumRecvBytes"` // example: 100 (Number of bytes received)
-	// NumSentBytes int64 `json:"NumSentBytes"` // example: 100 (Number of bytes sent)
+	PID          int64         `json:"PID"`                  // example: 1234
+	OwnerImage   string        `json:"OwnerImage,omitempty"` // example: "/usr/bin/curl"
+	Protocol     int64         `json:"Protocol"`             // example: 4
+	Saddr        string        `json:"Saddr"`                // example: "127.0.0.1"
+	Sport        int64         `json:"Sport"`                // example: 51234
+	Daddr        string        `json:"Daddr"`                // example: "127.0.0.1"
+	Dport        int64         `json:"Dport"`                // example: 80
+	Direction    Direction     `json:"Direction"`            // example: "OUTBOUND"
+	StartTime    time.Time     `json:"StartTime"`            // example: "2025-01-01T00:00:00Z"
+	EndTime      time.Time     `json:"EndTime"`              // example: "2025-01-01T00:00:05Z"
+	Duration     time.Duration `json:"Duration"`             // example: 5000000000 (nanoseconds)
+	LongLived    bool          `json:"LongLived,omitempty"`  // example: true (open for longer than expected)
+	Leaked       bool          `json:"Leaked,omitempty"`     // example: true (still open after its process exited)
+	NumRecvOps   int64         `json:"NumRecvOps"`           // example: 100 (Number of Receive operations)
+	NumSentOps   int64         `json:"NumSentOps"`           // example: 100 (Number of Send operations)
+	NumRecvBytes int64         `json:"NumRecvBytes"`         // example: 100 (Number of bytes received)
+	NumSentBytes int64         `json:"NumSentBytes"`         // example: 100 (Number of bytes sent)
 }
 
 // FileEntityModel defines the structure for file entities.
-// TODO: make file entity(trace) in polvo-architecture Implement FileEntityModel to represent file-related operations in polvo-architecture.
-// type FileEntityModel struct {
-// 	CommonEntityModel
-// 	NumReadOps    int64 `json:"NumReadOps"`    // example: 100 (Number of Read operations)
-// 	NumWriteOps   int64 `json:"NumWriteOps"`   // example: 100 (Number of Write operations)
-// 	NumReadBytes  int64 `json:"NumReadBytes"`  // example: 100 (Number of bytes read)
-// 	NumWriteBytes int64 `json:"NumWriteBytes"` // example: 100 (Number of bytes written)
-// }
+// A file is identified by its Inode and Path; a rename changes the Path and is recorded in RenameHistory.
+type FileEntityModel struct {
+	CommonEntityModel
+	Inode         int64        `json:"Inode"`                   // example: 17986650
+	Path          string       `json:"Path"`                    // example: "/var/log/syslog"
+	NumReadOps    int64        `json:"NumReadOps"`              // example: 100 (Number of Read operations)
+	NumWriteOps   int64        `json:"NumWriteOps"`             // example: 100 (Number of Write operations)
+	LastOpenerUID int64        `json:"LastOpenerUID"`           // example: 1200
+	LastOpenerPID int64        `json:"LastOpenerPID"`           // example: 8080
+	OwnerUID      int64        `json:"OwnerUID"`                // example: 1200
+	OwnerGID      int64        `json:"OwnerGID"`                // example: 1000
+	OwnerUsername string       `json:"OwnerUsername"`           // example: "root"
+	RenameHistory []FileRename `json:"RenameHistory,omitempty"` // renames of the file, oldest first
+}
+
+// FileRename records a rename of a file entity.
+type FileRename struct {
+	OldPath   string    `json:"OldPath"`   // example: "/var/log/syslog"
+	NewPath   string    `json:"NewPath"`   // example: "/var/log/syslog.backup"
+	PID       int64     `json:"PID"`       // example: 8080
+	UID       int64     `json:"UID"`       // example: 1200
+	Timestamp time.Time `json:"Timestamp"` // example: "2025-01-01T00:00:00Z"
+}
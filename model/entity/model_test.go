@@ -0,0 +1,31 @@
+package entityModel_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	entityModel "github.com/enki-polvo/polvo-logger/model/entity"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+// Test that entities written with the legacy string of matched rule IDs still decode
+func TestEntityLegacyMatchedRuleIDs(t *testing.T) {
+	data := `{"EntityType":"PROCESS","State":"MODIFIED","MatchedRuleIDs":"rule1","PID":1234}`
+
+	process := &entityModel.ProcessEntityModel{}
+	if err := json.Unmarshal([]byte(data), process); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	if process.State != state.MODIFIED || len(process.MatchedRuleIDs) != 1 || process.MatchedRuleIDs[0].RuleID != "rule1" {
+		t.Fatalf("Decoded entity does not match: %+v", process)
+	}
+
+	// the structured form is written back
+	encoded, err := json.Marshal(process.CommonEntityModel)
+	if err != nil {
+		t.Fatalf("Failed to marshal JSON: %v", err)
+	}
+	if want := `{"EntityType":"PROCESS","State":"MODIFIED","MatchedRuleIDs":[{"RuleID":"rule1","MatchedAt":"0001-01-01T00:00:00Z"}]}`; string(encoded) != want {
+		t.Fatalf("Encoded entity does not match:\n got: %s\nwant: %s", encoded, want)
+	}
+}
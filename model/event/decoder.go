@@ -0,0 +1,93 @@
+// model/event/decoder.go
+package eventModel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+)
+
+const (
+	ErrUnknownEventCode = "unknown event code: %d"
+	ErrDecodeMetadata   = "failed to decode metadata of '%s': %w"
+)
+
+var (
+	ErrNilWrapper = errors.New("cannot decode nil CommonModelWrapper")
+)
+
+// Decode decodes a JSON encoded event into its concrete event structure.
+// The EventCode of the header decides which structure is used, e.g.
+// PROC_CREATE is decoded into *ProcessCreateEvent and TCP_EVENT into *TcpEvent.
+// Third-party event types added with Register are decoded into *commonModel.CommonModel
+// holding their registered metadata structure.
+func Decode(data []byte) (Event, error) {
+	wrapper := &commonModel.CommonModelWrapper{}
+	if err := json.Unmarshal(data, wrapper); err != nil {
+		return nil, err
+	}
+	return DecodeWrapper(wrapper)
+}
+
+// DecodeWrapper converts an already unmarshalled CommonModelWrapper into its concrete event structure.
+// A wrapper written by an older SchemaVersion is migrated in place first.
+// It returns an error if the EventCode is unknown or the metadata cannot be decoded.
+func DecodeWrapper(wrapper *commonModel.CommonModelWrapper) (Event, error) {
+	if wrapper == nil {
+		return nil, ErrNilWrapper
+	}
+	// events written by an older schema version are migrated in place before decoding
+	if err := commonModel.Migrate(wrapper); err != nil {
+		return nil, err
+	}
+
+	switch wrapper.EventCode {
+	case commonModel.PROC_CREATE:
+		event := &ProcessCreateEvent{CommonHeader: wrapper.CommonHeader}
+		return decodeEvent(wrapper, event, &event.Metadata)
+	case commonModel.PROC_TERMINATE:
+		event := &ProcessTerminateEvent{CommonHeader: wrapper.CommonHeader}
+		return decodeEvent(wrapper, event, &event.Metadata)
+	case commonModel.PROC_BASH_READLINE:
+		event := &BashReadlineEvent{CommonHeader: wrapper.CommonHeader}
+		return decodeEvent(wrapper, event, &event.Metadata)
+	case commonModel.PROC_SERVICE:
+		event := &ServiceEvent{CommonHeader: wrapper.CommonHeader}
+		return decodeEvent(wrapper, event, &event.Metadata)
+	case commonModel.TCP_EVENT:
+		event := &TcpEvent{CommonHeader: wrapper.CommonHeader}
+		return decodeEvent(wrapper, event, &event.Metadata)
+	case commonModel.FILE_OPEN_EVENT:
+		event := &FileOpenEvent{CommonHeader: wrapper.CommonHeader}
+		return decodeEvent(wrapper, event, &event.Metadata)
+	case commonModel.FILE_RENAME_EVENT:
+		event := &FileRenameEvent{CommonHeader: wrapper.CommonHeader}
+		return decodeEvent(wrapper, event, &event.Metadata)
+	default:
+		// registered third-party event types are decoded into a CommonModel
+		metadata, isExists := commonModel.NewMetadata(wrapper.EventCode)
+		if !isExists {
+			return nil, fmt.Errorf(ErrUnknownEventCode, wrapper.EventCode)
+		}
+		event := &commonModel.CommonModel{CommonHeader: wrapper.CommonHeader, Metadata: metadata}
+		if wrapper.Metadata != nil {
+			if err := decodeMetadata(wrapper.Metadata, metadata); err != nil {
+				return nil, fmt.Errorf(ErrDecodeMetadata, wrapper.EventCode.String(), err)
+			}
+		}
+		return event, nil
+	}
+}
+
+// decodeEvent decodes the metadata map of the wrapper into dest, which must point
+// to the Metadata field of event. A missing metadata map leaves dest at its zero value.
+func decodeEvent[T Metadata](wrapper *commonModel.CommonModelWrapper, event Event, dest *T) (Event, error) {
+	if wrapper.Metadata != nil {
+		if err := DecodeMetadataAs(wrapper.Metadata, dest); err != nil {
+			return nil, fmt.Errorf(ErrDecodeMetadata, wrapper.EventCode.String(), err)
+		}
+	}
+	return event, nil
+}
@@ -0,0 +1,98 @@
+package eventModel_test
+
+import (
+	"testing"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	eventModel "github.com/enki-polvo/polvo-logger/model/event"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+// Test decoding of a process create event
+// Test that the EventCode selects the concrete event type and the metadata is filled
+func TestDecodeProcessCreate(t *testing.T) {
+	data := `{"EventCode":0,"EventName":"ProcessCreate","Source":"eBPF","Timestamp":"2025-06-09T16:54:26.270720921+09:00","Metadata":{"PID":97469,"PPID":97467,"UID":1000,"Username":"shhong","TGID":97469,"Commandline":"sh sed s/-//","ENV":"HOME=/home/shhong","Image":"/usr/bin/sed"}}`
+
+	event, err := eventModel.Decode([]byte(data))
+	if err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+
+	processCreate, ok := event.(*eventModel.ProcessCreateEvent)
+	if !ok {
+		t.Fatalf("Decoded event is not of type *ProcessCreateEvent: %T", event)
+	}
+	if processCreate.Source != "eBPF" {
+		t.Fatalf("Decoded source does not match: got %v, want %v", processCreate.Source, "eBPF")
+	}
+	if processCreate.Metadata.PID != 97469 || processCreate.Metadata.Image != "/usr/bin/sed" {
+		t.Fatalf("Decoded metadata does not match: %+v", processCreate.Metadata)
+	}
+}
+
+// Test decoding of every known event code
+// Test that each EventCode is mapped to its own concrete event type
+func TestDecodeAllEventCodes(t *testing.T) {
+	testCases := []struct {
+		code     commonModel.EventCode
+		metadata map[string]any
+		check    func(eventModel.Event) bool
+	}{
+		{commonModel.PROC_CREATE, map[string]any{"PID": 1}, func(e eventModel.Event) bool {
+			ev, ok := e.(*eventModel.ProcessCreateEvent)
+			return ok && ev.Metadata.PID == 1
+		}},
+		{commonModel.PROC_TERMINATE, map[string]any{"Ret": 2}, func(e eventModel.Event) bool {
+			ev, ok := e.(*eventModel.ProcessTerminateEvent)
+			return ok && ev.Metadata.Ret == 2
+		}},
+		{commonModel.PROC_BASH_READLINE, map[string]any{"Commandline": "ls"}, func(e eventModel.Event) bool {
+			ev, ok := e.(*eventModel.BashReadlineEvent)
+			return ok && ev.Metadata.Commandline == "ls"
+		}},
+		{commonModel.PROC_SERVICE, map[string]any{"TTY": "pts/0"}, func(e eventModel.Event) bool {
+			ev, ok := e.(*eventModel.ServiceEvent)
+			return ok && ev.Metadata.TTY == "pts/0"
+		}},
+		{commonModel.TCP_EVENT, map[string]any{"Op": 1}, func(e eventModel.Event) bool {
+			ev, ok := e.(*eventModel.TcpEvent)
+			return ok && ev.Metadata.Op == state.TCP_CONNECT
+		}},
+		{commonModel.FILE_OPEN_EVENT, map[string]any{"Path": "/var/log/syslog"}, func(e eventModel.Event) bool {
+			ev, ok := e.(*eventModel.FileOpenEvent)
+			return ok && ev.Metadata.Path == "/var/log/syslog"
+		}},
+		{commonModel.FILE_RENAME_EVENT, map[string]any{"NewPath": "/tmp/b"}, func(e eventModel.Event) bool {
+			ev, ok := e.(*eventModel.FileRenameEvent)
+			return ok && ev.Metadata.NewPath == "/tmp/b"
+		}},
+	}
+
+	for _, tc := range testCases {
+		wrapper := &commonModel.CommonModelWrapper{
+			CommonHeader: commonModel.CommonHeader{EventCode: tc.code, EventName: tc.code.String()},
+			Metadata:     tc.metadata,
+		}
+		event, err := eventModel.DecodeWrapper(wrapper)
+		if err != nil {
+			t.Fatalf("Failed to decode %v: %v", tc.code, err)
+		}
+		if !tc.check(event) {
+			t.Fatalf("Decoded %v has unexpected type or metadata: %+v", tc.code, event)
+		}
+	}
+}
+
+// Test decoding of an unknown event code
+// Test that an unknown EventCode returns an error
+func TestDecodeUnknownEventCode(t *testing.T) {
+	data := `{"EventCode":999,"EventName":"Unknown","Source":"eBPF","Metadata":{}}`
+
+	event, err := eventModel.Decode([]byte(data))
+	if err == nil {
+		t.Fatal("Expected error when decoding unknown event code, but got none")
+	}
+	if event != nil {
+		t.Fatal("Decoded event should be nil for unknown event code")
+	}
+}
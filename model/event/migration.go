@@ -0,0 +1,40 @@
+// model/event/migration.go
+package eventModel
+
+import (
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+)
+
+// init registers the migrations of the built-in event types.
+func init() {
+	// Schema version 0 file open events may use the keys of the former FileMetadata
+	// (TargetFilename, UID) and the former map key of FileOpenPurposeOp.
+	mustRegisterMigration(0, commonModel.FILE_OPEN_EVENT, func(wrapper *commonModel.CommonModelWrapper) error {
+		// events in the current shape without SchemaVersion are left as they are
+		if !isLegacyFileOpen(wrapper) {
+			return nil
+		}
+		wrapper.RenameMetadataKey("TargetFilename", "Path")
+		wrapper.RenameMetadataKey("UID", "FileOpenerUID")
+		wrapper.RenameMetadataKey("FileOpenPurposeOp", "FileOperationType")
+		return nil
+	})
+}
+
+// isLegacyFileOpen reports whether a file open event has the keys of the former FileMetadata,
+// which FileOpenMetadata does not have.
+func isLegacyFileOpen(wrapper *commonModel.CommonModelWrapper) bool {
+	for _, key := range []string{"TargetFilename", "UID"} {
+		if _, isExists := wrapper.Metadata[key]; isExists {
+			return true
+		}
+	}
+	return false
+}
+
+// mustRegisterMigration registers a built-in migration and panics on failure.
+func mustRegisterMigration(fromVersion uint32, code commonModel.EventCode, migrate commonModel.MigrationFunc) {
+	if err := commonModel.RegisterMigration(fromVersion, code, migrate); err != nil {
+		panic(err)
+	}
+}
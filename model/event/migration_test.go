@@ -0,0 +1,115 @@
+package eventModel_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	eventModel "github.com/enki-polvo/polvo-logger/model/event"
+)
+
+// Test decoding of a legacy file open event
+// Test that keys of the former FileMetadata are migrated to FileOpenMetadata
+func TestDecodeLegacyFileOpen(t *testing.T) {
+	data := `{"EventCode":5,"EventName":"FileOpenEvent","Source":"eBPF","Metadata":{"PID":8080,"UID":1200,"TargetFilename":"/var/log/syslog","FileOpenPurposeOp":2}}`
+
+	event, err := eventModel.Decode([]byte(data))
+	if err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+	fileOpen, ok := event.(*eventModel.FileOpenEvent)
+	if !ok {
+		t.Fatalf("Decoded event is not of type *FileOpenEvent: %T", event)
+	}
+	if fileOpen.Metadata.Path != "/var/log/syslog" || fileOpen.Metadata.FileOpenerUID != 1200 || fileOpen.Metadata.FileOpenPurposeOp != 2 {
+		t.Fatalf("Decoded metadata was not migrated: %+v", fileOpen.Metadata)
+	}
+	if fileOpen.SchemaVersion != commonModel.CURRENT_SCHEMA_VERSION {
+		t.Fatalf("Decoded schema version does not match: got %v, want %v", fileOpen.SchemaVersion, commonModel.CURRENT_SCHEMA_VERSION)
+	}
+
+	cm := &commonModel.CommonModel{}
+	if err = json.Unmarshal([]byte(data), cm); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	metadata, ok := cm.Metadata.(*eventModel.FileOpenMetadata)
+	if !ok || metadata.Path != "/var/log/syslog" {
+		t.Fatalf("Unmarshalled metadata was not migrated: %+v", cm.Metadata)
+	}
+}
+
+// Test migration of a file open event in the current shape without SchemaVersion
+// Test that only events with the keys of the former FileMetadata are migrated
+func TestMigrationKeepsCurrentFileOpen(t *testing.T) {
+	wrapper := &commonModel.CommonModelWrapper{
+		CommonHeader: commonModel.CommonHeader{EventCode: commonModel.FILE_OPEN_EVENT},
+		Metadata:     map[string]any{"PID": 8080, "FileOpenerUID": 1200, "Path": "/var/log/syslog", "FileOpenPurposeOp": 2},
+	}
+	if err := commonModel.Migrate(wrapper); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if _, isExists := wrapper.Metadata["FileOpenPurposeOp"]; !isExists || len(wrapper.Metadata) != 4 {
+		t.Fatalf("Metadata in the current shape was migrated: %v", wrapper.Metadata)
+	}
+	if wrapper.SchemaVersion != commonModel.CURRENT_SCHEMA_VERSION {
+		t.Fatalf("Schema version does not match: got %v, want %v", wrapper.SchemaVersion, commonModel.CURRENT_SCHEMA_VERSION)
+	}
+}
+
+// Test migration that splits an event type
+// Test that a migration can change the EventCode of an older event
+func TestMigrationChangesEventCode(t *testing.T) {
+	type mountMetadata struct {
+		Device string `json:"Device" mapstructure:"Device"`
+	}
+	legacyCode := commonModel.USER_EVENT_CODE_MIN + 10
+	newCode := commonModel.USER_EVENT_CODE_MIN + 11
+
+	if err := eventModel.Register(legacyCode, "LegacyDeviceEvent", func() any { return &mountMetadata{} }); err != nil {
+		t.Fatalf("Failed to register event type: %v", err)
+	}
+	if err := eventModel.Register(newCode, "DeviceMount", func() any { return &mountMetadata{} }); err != nil {
+		t.Fatalf("Failed to register event type: %v", err)
+	}
+	err := commonModel.RegisterMigration(0, legacyCode, func(wrapper *commonModel.CommonModelWrapper) error {
+		wrapper.EventCode = newCode
+		wrapper.EventName = newCode.String()
+		wrapper.RenameMetadataKey("Dev", "Device")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to register migration: %v", err)
+	}
+	if err = commonModel.RegisterMigration(0, legacyCode, func(*commonModel.CommonModelWrapper) error { return nil }); err == nil {
+		t.Fatal("Expected error when registering a duplicate migration, but got none")
+	}
+	if err = commonModel.RegisterMigration(commonModel.CURRENT_SCHEMA_VERSION, legacyCode, func(*commonModel.CommonModelWrapper) error { return nil }); err == nil {
+		t.Fatal("Expected error when registering a migration from the current version, but got none")
+	}
+
+	cm := &commonModel.CommonModel{}
+	if err = json.Unmarshal([]byte(`{"EventCode":1010,"Source":"udev","Metadata":{"Dev":"sdb1"}}`), cm); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	metadata, ok := cm.Metadata.(*mountMetadata)
+	if cm.EventCode != newCode || !ok || metadata.Device != "sdb1" {
+		t.Fatalf("Unmarshalled event was not migrated: %+v, metadata: %+v", cm, cm.Metadata)
+	}
+}
+
+// Test that migrated metadata keeps integers above 2^53
+func TestMigrationKeepsLargeIntegers(t *testing.T) {
+	for _, data := range []string{
+		`{"EventCode":5,"Metadata":{"Inode":9007199254740993}}`,
+		`{"EventCode":5,"SchemaVersion":1,"Metadata":{"Inode":9007199254740993}}`,
+	} {
+		cm := &commonModel.CommonModel{}
+		if err := json.Unmarshal([]byte(data), cm); err != nil {
+			t.Fatalf("Failed to unmarshal JSON: %v", err)
+		}
+		metadata, ok := cm.Metadata.(*eventModel.FileOpenMetadata)
+		if !ok || metadata.Inode != 9007199254740993 {
+			t.Fatalf("Unmarshalled Inode does not match: %+v", cm.Metadata)
+		}
+	}
+}
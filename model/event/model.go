@@ -2,32 +2,37 @@
 package eventModel
 
 import (
+	"maps"
+	"reflect"
+
 	commonModel "github.com/enki-polvo/polvo-logger/model"
 	state "github.com/enki-polvo/polvo-logger/model/state"
 	"github.com/mitchellh/mapstructure"
 )
 
 // Event defines the interface for all event types.
-type Event any
+type Event = any
 
-type Metadata interface {
-	ProcessCreateMetadata | ProcessTerminateMetadata | BashReadlineMetadata | ServiceMetadata | TcpMetadata | FileOpenMetadata | FileRenameMetadata
-}
+// Metadata is the constraint of the metadata structures accepted by the decoders.
+// It is unrestricted, so that the metadata of registered third-party event types are accepted as well.
+type Metadata any
 
 // --------------------------------------------------
 // Event Metadata
 // --------------------------------------------------
 
 // ProcessCreateMetadata defines the Metadata structure for process creation events.
+// ProcessKey is optional: trackers derive it from the PID and the start time of the process when it is empty.
 type ProcessCreateMetadata struct {
-	PID         int64  `json:"PID" mapstructure:"PID"`                 // example: 1234
-	PPID        int64  `json:"PPID" mapstructure:"PPID"`               // example: 4
-	UID         int64  `json:"UID" mapstructure:"UID"`                 // example: 1000
-	Username    string `json:"Username" mapstructure:"Username"`       // example: "root"
-	TGID        int64  `json:"TGID" mapstructure:"TGID"`               // example: 1234
-	Commandline string `json:"Commandline" mapstructure:"Commandline"` // example: "bash rm -rf /tmp"
-	ENV         string `json:"ENV" mapstructure:"ENV"`                 // example: "PATH=/usr/bin:/bin"
-	Image       string `json:"Image" mapstructure:"Image"`             // example: "/usr/bin/bash"
+	PID         int64                  `json:"PID" mapstructure:"PID"`                         // example: 1234
+	PPID        int64                  `json:"PPID" mapstructure:"PPID"`                       // example: 4
+	UID         int64                  `json:"UID" mapstructure:"UID"`                         // example: 1000
+	Username    string                 `json:"Username" mapstructure:"Username"`               // example: "root"
+	TGID        int64                  `json:"TGID" mapstructure:"TGID"`                       // example: 1234
+	Commandline string                 `json:"Commandline" mapstructure:"Commandline"`         // example: "bash rm -rf /tmp"
+	ENV         string                 `json:"ENV" mapstructure:"ENV"`                         // example: "PATH=/usr/bin:/bin"
+	Image       string                 `json:"Image" mapstructure:"Image"`                     // example: "/usr/bin/bash"
+	ProcessKey  commonModel.ProcessKey `json:"ProcessKey,omitempty" mapstructure:"ProcessKey"` // example: "1234@1735689605000"
 }
 
 // ProcessTerminateMetadata defines the Metadata structure for process termination events.
@@ -63,7 +68,7 @@ type TcpMetadata struct {
 	Saddr    string      `json:"Saddr" mapstructure:"Saddr"`       // example: "127.0.0.1"
 	Sport    int64       `json:"Sport" mapstructure:"Sport"`       // example: 80
 	Protocol int64       `json:"Protocol" mapstructure:"Protocol"` // example: 4
-	Op       state.TcpOp `json:"Op" mapstructure:"Op"`             // example: "CONNECT" "DISCONNECT" "ACCEPT" etc..
+	Op       state.TcpOp `json:"Op" mapstructure:"Op"`             // example: "TCP_CONNECT" "TCP_DISCONNECT" "TCP_ACCEPT" etc..
 }
 
 // FileOpenMetadata defines the Metadata structure for file open events
@@ -99,12 +104,57 @@ type FileRenameMetadata struct {
 
 // DecodeMetadataAs decodes the map into a Metadata.
 // It uses mapstructure to decode the Metadata field into the appropriate structure.
+// Enums are accepted both as numbers and as names (e.g. "TCP_CONNECT").
+// Fields whose JSON key differs from their mapstructure key (e.g. FileOperationType and FileOpenPurposeOp)
+// are accepted under both keys, so maps decoded from JSON are decoded as well.
 // Warning: This function does not return an error when attempting to decode with the wrong type due to limitations in mapstructure.
 func DecodeMetadataAs[T Metadata](origin map[string]any, dest *T) (err error) {
-	err = mapstructure.Decode(origin, dest)
+	return decodeMetadata(origin, dest)
+}
+
+// decodeMetadata decodes the map into dest, which must be a pointer to a metadata structure.
+func decodeMetadata(origin map[string]any, dest any) (err error) {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.TextUnmarshallerHookFunc(),
+		Result:     dest,
+	})
+	if err != nil {
+		return err
+	}
+	if structType := reflect.TypeOf(dest).Elem(); structType.Kind() == reflect.Struct {
+		origin = withJSONAliases(structType, origin)
+	}
+	err = decoder.Decode(origin)
 	return err
 }
 
+// withJSONAliases returns origin with the values given under the JSON key of a field
+// moved to its mapstructure key, when the keys differ and the mapstructure key is absent.
+// origin is not modified.
+func withJSONAliases(structType reflect.Type, origin map[string]any) map[string]any {
+	aliased, isCloned := origin, false
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		key, jsonKey := fieldKey(field), jsonFieldKey(field)
+		if jsonKey == "" || jsonKey == key {
+			continue
+		}
+		value, isExists := origin[jsonKey]
+		if !isExists {
+			continue
+		}
+		if _, isExists = origin[key]; isExists {
+			continue
+		}
+		if !isCloned {
+			aliased, isCloned = maps.Clone(origin), true
+		}
+		aliased[key] = value
+		delete(aliased, jsonKey)
+	}
+	return aliased
+}
+
 // --------------------------------------------------
 // System events Metadata
 //
@@ -0,0 +1,44 @@
+// model/event/registry.go
+package eventModel
+
+import (
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+// Register registers a third-party event type.
+// code must be at least commonModel.USER_EVENT_CODE_MIN, and neither code nor name may be registered already.
+// newMetadata must return a pointer to a new metadata structure of the event type.
+// Once registered, the event type is known to eventPool, Decode, the JSON codec of
+// commonModel.CommonModel and EventCode.String().
+func Register(code commonModel.EventCode, name string, newMetadata func() any) error {
+	return commonModel.RegisterEventType(code, name, newMetadata)
+}
+
+// mustRegister registers a built-in event type and panics on failure.
+func mustRegister(code commonModel.EventCode, newMetadata func() any) {
+	if err := commonModel.RegisterEventType(code, code.String(), newMetadata); err != nil {
+		panic(err)
+	}
+}
+
+// init registers the built-in event types.
+func init() {
+	mustRegister(commonModel.PROC_CREATE, func() any { return &ProcessCreateMetadata{} })
+	mustRegister(commonModel.PROC_TERMINATE, func() any { return &ProcessTerminateMetadata{} })
+	mustRegister(commonModel.PROC_BASH_READLINE, func() any { return &BashReadlineMetadata{} })
+	mustRegister(commonModel.PROC_SERVICE, func() any { return &ServiceMetadata{} })
+	mustRegister(commonModel.TCP_EVENT, func() any {
+		// Initialize the metadata Opcode for TCP events
+		return &TcpMetadata{
+			Op: state.TCP_OP_UNSET, // default value
+		}
+	})
+	mustRegister(commonModel.FILE_OPEN_EVENT, func() any {
+		// Initializes the metadata Opcode for File Open events
+		return &FileOpenMetadata{
+			FileOpenPurposeOp: state.FILE_OPEN_TO_UNSET, // default value
+		}
+	})
+	mustRegister(commonModel.FILE_RENAME_EVENT, func() any { return &FileRenameMetadata{} })
+}
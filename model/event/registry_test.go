@@ -0,0 +1,88 @@
+package eventModel_test
+
+import (
+	"testing"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	eventModel "github.com/enki-polvo/polvo-logger/model/event"
+)
+
+// CustomMetadata is the metadata of a third-party event type used in tests.
+type CustomMetadata struct {
+	PID    int64  `json:"PID" mapstructure:"PID"`
+	Device string `json:"Device" mapstructure:"Device"`
+}
+
+// Test registration of a third-party event type
+// Test that a registered event type is known to String, ParseEventCode and Decode
+func TestRegisterEventType(t *testing.T) {
+	code := commonModel.USER_EVENT_CODE_MIN + 1
+	err := eventModel.Register(code, "UsbMount", func() any { return &CustomMetadata{} })
+	if err != nil {
+		t.Fatalf("Failed to register event type: %v", err)
+	}
+
+	if code.String() != "UsbMount" {
+		t.Fatalf("Registered name does not match: got %v, want %v", code.String(), "UsbMount")
+	}
+	parsed, err := commonModel.ParseEventCode("UsbMount")
+	if err != nil || parsed != code {
+		t.Fatalf("Failed to parse registered name: got %v, %v", parsed, err)
+	}
+
+	event, err := eventModel.Decode([]byte(`{"EventCode":1001,"EventName":"UsbMount","Source":"udev","Metadata":{"PID":1,"Device":"sdb1"}}`))
+	if err != nil {
+		t.Fatalf("Failed to decode registered event: %v", err)
+	}
+	cm, ok := event.(*commonModel.CommonModel)
+	if !ok {
+		t.Fatalf("Decoded event is not of type *CommonModel: %T", event)
+	}
+	metadata, ok := cm.Metadata.(*CustomMetadata)
+	if !ok || metadata.Device != "sdb1" {
+		t.Fatalf("Decoded metadata does not match: %+v", cm.Metadata)
+	}
+}
+
+// Test registration of invalid event types
+// Test that reserved codes, duplicate codes and duplicate names are rejected
+func TestRegisterEventTypeRejected(t *testing.T) {
+	newMetadata := func() any { return &CustomMetadata{} }
+
+	if err := eventModel.Register(commonModel.EventCode(42), "Reserved", newMetadata); err == nil {
+		t.Fatal("Expected error when registering a reserved code, but got none")
+	}
+	if err := eventModel.Register(commonModel.PROC_CREATE, "ProcessCreate2", newMetadata); err == nil {
+		t.Fatal("Expected error when registering a built-in code, but got none")
+	}
+
+	code := commonModel.USER_EVENT_CODE_MIN + 2
+	if err := eventModel.Register(code, "UsbUnmount", newMetadata); err != nil {
+		t.Fatalf("Failed to register event type: %v", err)
+	}
+	if err := eventModel.Register(code, "UsbUnmount2", newMetadata); err == nil {
+		t.Fatal("Expected error when registering a duplicate code, but got none")
+	}
+	if err := eventModel.Register(code+1, "UsbUnmount", newMetadata); err == nil {
+		t.Fatal("Expected error when registering a duplicate name, but got none")
+	}
+	if err := eventModel.Register(code+1, "TcpEvent", newMetadata); err == nil {
+		t.Fatal("Expected error when registering a built-in name, but got none")
+	}
+
+	// the decoders need a non-nil pointer to a struct
+	invalid := map[string]func() any{
+		"value":       func() any { return CustomMetadata{} },
+		"nil":         func() any { return nil },
+		"nil pointer": func() any { return (*CustomMetadata)(nil) },
+		"map pointer": func() any { return &map[string]any{} },
+	}
+	for kind, newInvalid := range invalid {
+		if err := eventModel.Register(code+2, "UsbInvalid", newInvalid); err == nil {
+			t.Fatalf("Expected error when registering a %s metadata, but got none", kind)
+		}
+	}
+	if _, err := commonModel.ParseEventCode("UsbInvalid"); err == nil {
+		t.Fatal("Expected error when parsing a rejected name, but got none")
+	}
+}
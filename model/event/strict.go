@@ -0,0 +1,197 @@
+// model/event/strict.go
+package eventModel
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+)
+
+const (
+	ErrFieldUnknown      = "unknown field"
+	ErrFieldMissing      = "missing required field"
+	ErrFieldTypeMismatch = "expected %s, got %T"
+	ErrFieldOutOfRange   = "value %v is out of range for %s"
+	ErrMetadataNotStruct = "metadata type %v is not a struct"
+)
+
+// FieldError describes a single offending field of a metadata payload.
+type FieldError = commonModel.FieldError
+
+// FieldErrors collects every FieldError found in a metadata payload.
+type FieldErrors = commonModel.FieldErrors
+
+// DecodeMetadataStrict decodes the map into a Metadata like DecodeMetadataAs, but fails
+// on unknown keys, wrong primitive types, missing fields and out-of-range enum values.
+// Every field of the metadata structure is required, except the ones tagged json:",omitempty".
+// The returned error is a FieldErrors listing every offending field.
+func DecodeMetadataStrict[T Metadata](origin map[string]any, dest *T) (err error) {
+	var fieldErrs FieldErrors
+
+	structType := reflect.TypeOf(dest).Elem()
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf(ErrMetadataNotStruct, structType)
+	}
+	fieldErrs = checkStrict(structType, origin)
+	if len(fieldErrs) > 0 {
+		return fieldErrs
+	}
+	return DecodeMetadataAs(origin, dest)
+}
+
+// checkStrict compares origin against the mapstructure fields of structType.
+func checkStrict(structType reflect.Type, origin map[string]any) (fieldErrs FieldErrors) {
+	known := make(map[string]struct{}, structType.NumField())
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := fieldKey(field)
+		known[key] = struct{}{}
+
+		value, isExists := origin[key]
+		// the JSON key of the field is accepted as well, see DecodeMetadataAs
+		if jsonKey := jsonFieldKey(field); jsonKey != "" && jsonKey != key {
+			known[jsonKey] = struct{}{}
+			if jsonValue, isJSONExists := origin[jsonKey]; !isExists && isJSONExists {
+				key, value, isExists = jsonKey, jsonValue, true
+			}
+		}
+		if !isExists {
+			if !isOptional(field) {
+				fieldErrs = append(fieldErrs, &FieldError{Field: key, Reason: ErrFieldMissing})
+			}
+			continue
+		}
+		if reason := checkValue(field.Type, value); reason != "" {
+			fieldErrs = append(fieldErrs, &FieldError{Field: key, Reason: reason})
+		}
+	}
+
+	for key := range origin {
+		if _, isExists := known[key]; !isExists {
+			fieldErrs = append(fieldErrs, &FieldError{Field: key, Reason: ErrFieldUnknown})
+		}
+	}
+
+	sort.Slice(fieldErrs, func(i, j int) bool {
+		return fieldErrs[i].Field < fieldErrs[j].Field
+	})
+	return fieldErrs
+}
+
+// fieldKey returns the map key mapstructure uses for the field.
+func fieldKey(field reflect.StructField) string {
+	tag := field.Tag.Get("mapstructure")
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// jsonFieldKey returns the JSON key of the field, or an empty string if it has none.
+func jsonFieldKey(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// isOptional reports whether the field may be missing, i.e. its json tag has the omitempty option.
+func isOptional(field reflect.StructField) bool {
+	_, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return slices.Contains(strings.Split(options, ","), "omitempty")
+}
+
+// checkValue returns the reason why value cannot be stored in a field of fieldType,
+// or an empty string if it can.
+func checkValue(fieldType reflect.Type, value any) string {
+	// enums may be given by name
+	if text, ok := value.(string); ok && fieldType.Kind() != reflect.String {
+		enum, ok := reflect.New(fieldType).Interface().(encoding.TextUnmarshaler)
+		if !ok {
+			return fmt.Sprintf(ErrFieldTypeMismatch, fieldType.Kind(), value)
+		}
+		if err := enum.UnmarshalText([]byte(text)); err != nil {
+			return err.Error()
+		}
+		parsed := reflect.ValueOf(enum).Elem()
+		if !parsed.CanInt() {
+			return ""
+		}
+		value = parsed.Int()
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			return fmt.Sprintf(ErrFieldTypeMismatch, "string", value)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, ok := toInt64(value)
+		if !ok {
+			return fmt.Sprintf(ErrFieldTypeMismatch, "integer", value)
+		}
+		if reflect.Zero(fieldType).OverflowInt(number) {
+			return fmt.Sprintf(ErrFieldOutOfRange, value, fieldType.Name())
+		}
+		// enums report an empty name for values they do not define
+		enum := reflect.New(fieldType).Elem()
+		enum.SetInt(number)
+		if stringer, ok := enum.Interface().(fmt.Stringer); ok && stringer.String() == "" {
+			return fmt.Sprintf(ErrFieldOutOfRange, value, fieldType.Name())
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf(ErrFieldTypeMismatch, "bool", value)
+		}
+	}
+	return ""
+}
+
+// toInt64 converts integral values produced by encoding/json or Go code into an int64.
+func toInt64(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(v), true
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, false
+		}
+		return int64(v), true
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
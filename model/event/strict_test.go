@@ -0,0 +1,119 @@
+package eventModel_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	eventModel "github.com/enki-polvo/polvo-logger/model/event"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+// Test strict decoding of a valid payload
+// Test that a complete and well-typed payload decodes without error
+func TestDecodeMetadataStrict(t *testing.T) {
+	data := `{"PID":1234,"Daddr":"127.0.0.1","Dport":80,"Saddr":"127.0.0.1","Sport":51234,"Protocol":4,"Op":1}`
+
+	origin := map[string]any{}
+	if err := json.Unmarshal([]byte(data), &origin); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+
+	dest := eventModel.TcpMetadata{}
+	if err := eventModel.DecodeMetadataStrict(origin, &dest); err != nil {
+		t.Fatalf("Failed to decode metadata strictly: %v", err)
+	}
+	if dest.Op != state.TCP_CONNECT || dest.Dport != 80 {
+		t.Fatalf("Decoded metadata does not match: %+v", dest)
+	}
+}
+
+// Test strict decoding of a payload without optional fields
+// Test that fields tagged omitempty may be missing
+func TestDecodeMetadataStrictOptionalField(t *testing.T) {
+	data := `{"PID":1234,"PPID":1,"UID":0,"Username":"root","TGID":1234,"Commandline":"bash","ENV":"","Image":"/usr/bin/bash"}`
+
+	origin := map[string]any{}
+	if err := json.Unmarshal([]byte(data), &origin); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+
+	dest := eventModel.ProcessCreateMetadata{}
+	if err := eventModel.DecodeMetadataStrict(origin, &dest); err != nil {
+		t.Fatalf("Failed to decode metadata without ProcessKey strictly: %v", err)
+	}
+}
+
+// Test strict decoding of an invalid payload
+// Test that every offending field is reported
+func TestDecodeMetadataStrictReportsEveryField(t *testing.T) {
+	data := `{"PID":"invalid_type","Daddr":"127.0.0.1","Dport":80.5,"Saddr":"127.0.0.1","Protocol":4,"Op":42,"Extra":true}`
+
+	origin := map[string]any{}
+	if err := json.Unmarshal([]byte(data), &origin); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+
+	dest := eventModel.TcpMetadata{}
+	err := eventModel.DecodeMetadataStrict(origin, &dest)
+	if err == nil {
+		t.Fatal("Expected an error due to invalid fields, but got none")
+	}
+
+	var fieldErrs eventModel.FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("Expected FieldErrors, got %T", err)
+	}
+
+	expected := []string{"Dport", "Extra", "Op", "PID", "Sport"}
+	if len(fieldErrs) != len(expected) {
+		t.Fatalf("Unexpected number of field errors: got %v, want %v", fieldErrs, expected)
+	}
+	for i, field := range expected {
+		if fieldErrs[i].Field != field {
+			t.Fatalf("Unexpected field error at %d: got %v, want %v", i, fieldErrs[i].Field, field)
+		}
+	}
+	t.Logf("Expected error occurred: %v", err)
+}
+
+// Test strict decoding of an out-of-range file open purpose
+// Test that enum values without a name are rejected
+func TestDecodeMetadataStrictEnumRange(t *testing.T) {
+	origin := map[string]any{
+		"PID": 1, "FileOpenerUID": 0, "FileOpenerGID": 0, "FileOpenerUsername": "root",
+		"FileOwnerUID": 0, "FileOwnerGID": 0, "FileOwnerUsername": "root", "Mode": 0, "Fmode": 0,
+		"FileOperationType": 7, "Inode": 1, "Size": 0, "ProcessName": "bash", "Path": "/tmp/a",
+	}
+
+	dest := eventModel.FileOpenMetadata{}
+	err := eventModel.DecodeMetadataStrict(origin, &dest)
+
+	var fieldErr *eventModel.FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "FileOperationType" {
+		t.Fatalf("Expected FieldError for FileOperationType, got %v", err)
+	}
+}
+
+// Test that the file open purpose is decoded under both its mapstructure key and its JSON key
+func TestDecodeMetadataFileOpenPurposeKeys(t *testing.T) {
+	for _, key := range []string{"FileOpenPurposeOp", "FileOperationType"} {
+		origin := map[string]any{
+			"PID": 1, "FileOpenerUID": 0, "FileOpenerGID": 0, "FileOpenerUsername": "root",
+			"FileOwnerUID": 0, "FileOwnerGID": 0, "FileOwnerUsername": "root", "Mode": 0, "Fmode": 0,
+			key: 2, "Inode": 1, "Size": 0, "ProcessName": "bash", "Path": "/tmp/a",
+		}
+
+		dest := eventModel.FileOpenMetadata{}
+		if err := eventModel.DecodeMetadataAs(origin, &dest); err != nil || dest.FileOpenPurposeOp != state.FILE_OPEN_TO_WRITE {
+			t.Fatalf("Failed to decode %s: %+v, %v", key, dest, err)
+		}
+		dest = eventModel.FileOpenMetadata{}
+		if err := eventModel.DecodeMetadataStrict(origin, &dest); err != nil || dest.FileOpenPurposeOp != state.FILE_OPEN_TO_WRITE {
+			t.Fatalf("Failed to decode %s strictly: %+v, %v", key, dest, err)
+		}
+		if _, isExists := origin[key]; !isExists || len(origin) != 14 {
+			t.Fatalf("The decoded map was modified: %v", origin)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// model/event/validate.go
+package eventModel
+
+import (
+	"net/netip"
+	"path"
+
+	state "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+const (
+	ErrValueNegative  = "must not be negative"
+	ErrPortRange      = "must be within 0-65535"
+	ErrNotIPAddress   = "is not an IP address"
+	ErrRelativePath   = "must be an absolute path"
+	ErrEmptyValue     = "must not be empty"
+	ErrOpUnset        = "must be set"
+	ErrOpUndefined    = "is not a defined operation"
+	ErrSameRenamePath = "must differ from OldPath"
+)
+
+const (
+	maxPort = 65535
+)
+
+// fieldChecker collects the FieldErrors of a metadata structure.
+type fieldChecker struct {
+	errs FieldErrors
+}
+
+// check records a FieldError for field when ok is false.
+func (c *fieldChecker) check(ok bool, field, reason string) {
+	if !ok {
+		c.errs = append(c.errs, &FieldError{Field: field, Reason: reason})
+	}
+}
+
+func (c *fieldChecker) nonNegative(value int64, field string) {
+	c.check(value >= 0, field, ErrValueNegative)
+}
+
+func (c *fieldChecker) port(value int64, field string) {
+	c.check(value >= 0 && value <= maxPort, field, ErrPortRange)
+}
+
+func (c *fieldChecker) ipAddress(value string, field string) {
+	_, err := netip.ParseAddr(value)
+	c.check(err == nil, field, ErrNotIPAddress)
+}
+
+// absolutePath checks that value is an absolute path. Empty values are only accepted if optional.
+func (c *fieldChecker) absolutePath(value string, field string, optional bool) {
+	if value == "" {
+		c.check(optional, field, ErrEmptyValue)
+		return
+	}
+	c.check(path.IsAbs(value), field, ErrRelativePath)
+}
+
+// err returns the collected FieldErrors, or nil if there are none.
+func (c *fieldChecker) err() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return c.errs
+}
+
+// Validate checks that the metadata describes a sane process creation.
+func (m *ProcessCreateMetadata) Validate() error {
+	c := &fieldChecker{}
+	c.nonNegative(m.PID, "PID")
+	c.nonNegative(m.PPID, "PPID")
+	c.nonNegative(m.UID, "UID")
+	c.nonNegative(m.TGID, "TGID")
+	c.absolutePath(m.Image, "Image", true)
+	return c.err()
+}
+
+// Validate checks that the metadata describes a sane process termination.
+func (m *ProcessTerminateMetadata) Validate() error {
+	c := &fieldChecker{}
+	c.nonNegative(m.PID, "PID")
+	c.nonNegative(m.UID, "UID")
+	return c.err()
+}
+
+// Validate checks that the metadata describes a sane bash readline.
+func (m *BashReadlineMetadata) Validate() error {
+	c := &fieldChecker{}
+	c.nonNegative(m.PID, "PID")
+	c.nonNegative(m.UID, "UID")
+	return c.err()
+}
+
+// Validate checks that the metadata describes a sane service.
+func (m *ServiceMetadata) Validate() error {
+	c := &fieldChecker{}
+	c.nonNegative(m.PID, "PID")
+	c.nonNegative(m.UID, "UID")
+	c.absolutePath(m.Image, "Image", true)
+	return c.err()
+}
+
+// Validate checks that the metadata describes a sane TCP operation.
+func (m *TcpMetadata) Validate() error {
+	c := &fieldChecker{}
+	c.nonNegative(m.PID, "PID")
+	c.ipAddress(m.Daddr, "Daddr")
+	c.port(m.Dport, "Dport")
+	c.ipAddress(m.Saddr, "Saddr")
+	c.port(m.Sport, "Sport")
+	c.nonNegative(m.Protocol, "Protocol")
+	c.check(m.Op != state.TCP_OP_UNSET, "Op", ErrOpUnset)
+	c.check(m.Op.String() != "", "Op", ErrOpUndefined)
+	return c.err()
+}
+
+// Validate checks that the metadata describes a sane file open.
+func (m *FileOpenMetadata) Validate() error {
+	c := &fieldChecker{}
+	c.nonNegative(m.PID, "PID")
+	c.nonNegative(m.FileOpenerUID, "FileOpenerUID")
+	c.nonNegative(m.FileOpenerGID, "FileOpenerGID")
+	c.nonNegative(m.FileOwnerUID, "FileOwnerUID")
+	c.nonNegative(m.FileOwnerGID, "FileOwnerGID")
+	c.nonNegative(m.Mode, "Mode")
+	c.nonNegative(m.Fmode, "Fmode")
+	c.check(m.FileOpenPurposeOp != state.FILE_OPEN_TO_UNSET, "FileOperationType", ErrOpUnset)
+	c.check(m.FileOpenPurposeOp.String() != "", "FileOperationType", ErrOpUndefined)
+	c.nonNegative(m.Inode, "Inode")
+	c.nonNegative(m.Size, "Size")
+	c.absolutePath(m.Path, "Path", false)
+	return c.err()
+}
+
+// Validate checks that the metadata describes a sane file rename.
+func (m *FileRenameMetadata) Validate() error {
+	c := &fieldChecker{}
+	c.nonNegative(m.PID, "PID")
+	c.nonNegative(m.UID, "UID")
+	c.nonNegative(m.GID, "GID")
+	c.absolutePath(m.OldPath, "OldPath", false)
+	c.absolutePath(m.NewPath, "NewPath", false)
+	c.check(m.OldPath == "" || m.OldPath != m.NewPath, "NewPath", ErrSameRenamePath)
+	return c.err()
+}
@@ -0,0 +1,78 @@
+package eventModel_test
+
+import (
+	"errors"
+	"testing"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	eventModel "github.com/enki-polvo/polvo-logger/model/event"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+// Test validation of valid metadata
+// Test that sane metadata structures pass validation
+func TestValidateMetadata(t *testing.T) {
+	validators := []commonModel.Validator{
+		&eventModel.ProcessCreateMetadata{PID: 1234, PPID: 4, UID: 1000, TGID: 1234, Image: "/usr/bin/bash"},
+		&eventModel.TcpMetadata{PID: 1234, Daddr: "10.0.0.1", Dport: 443, Saddr: "::1", Sport: 51234, Protocol: 4, Op: state.TCP_CONNECT},
+		&eventModel.FileOpenMetadata{PID: 8080, FileOpenPurposeOp: state.FILE_OPEN_TO_WRITE, Inode: 17986650, Path: "/var/log/syslog"},
+		&eventModel.FileRenameMetadata{PID: 8080, OldPath: "/var/log/syslog", NewPath: "/var/log/syslog.backup"},
+	}
+
+	for _, validator := range validators {
+		if err := validator.Validate(); err != nil {
+			t.Fatalf("Failed to validate %T: %v", validator, err)
+		}
+	}
+}
+
+// Test validation of invalid TCP metadata
+// Test that every offending field is reported
+func TestValidateTcpMetadata(t *testing.T) {
+	metadata := &eventModel.TcpMetadata{PID: -1, Daddr: "localhost", Dport: 70000, Saddr: "127.0.0.1", Sport: 80}
+
+	err := metadata.Validate()
+	var fieldErrs eventModel.FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("Expected FieldErrors, got %v", err)
+	}
+
+	expected := []string{"PID", "Daddr", "Dport", "Op"}
+	if len(fieldErrs) != len(expected) {
+		t.Fatalf("Unexpected field errors: got %v, want %v", fieldErrs, expected)
+	}
+	for i, field := range expected {
+		if fieldErrs[i].Field != field {
+			t.Fatalf("Unexpected field error at %d: got %v, want %v", i, fieldErrs[i].Field, field)
+		}
+	}
+}
+
+// Test validation of a file rename with identical paths
+// Test that renaming a file onto itself is rejected
+func TestValidateFileRenameSamePath(t *testing.T) {
+	metadata := &eventModel.FileRenameMetadata{PID: 1, OldPath: "/tmp/a", NewPath: "/tmp/a"}
+
+	var fieldErr *eventModel.FieldError
+	if err := metadata.Validate(); !errors.As(err, &fieldErr) || fieldErr.Field != "NewPath" {
+		t.Fatalf("Expected FieldError for NewPath, got %v", err)
+	}
+}
+
+// Test validation of a CommonModel
+// Test that metadata errors are prefixed and mismatched metadata types are reported
+func TestValidateCommonModel(t *testing.T) {
+	event := &commonModel.CommonModel{
+		CommonHeader: commonModel.CommonHeader{EventCode: commonModel.FILE_OPEN_EVENT},
+		Metadata:     &eventModel.FileOpenMetadata{PID: 1, FileOpenPurposeOp: state.FILE_OPEN_TO_READ, Path: "relative/path"},
+	}
+	var fieldErr *eventModel.FieldError
+	if err := event.Validate(); !errors.As(err, &fieldErr) || fieldErr.Field != "Metadata.Path" {
+		t.Fatalf("Expected FieldError for Metadata.Path, got %v", err)
+	}
+
+	event.Metadata = &eventModel.TcpMetadata{}
+	if err := event.Validate(); !errors.As(err, &fieldErr) || fieldErr.Field != "Metadata" {
+		t.Fatalf("Expected FieldError for Metadata, got %v", err)
+	}
+}
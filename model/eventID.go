@@ -0,0 +1,88 @@
+// model/eventID.go
+package commonModel
+
+import (
+	"crypto/rand"
+	"sync"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// EventIDGenerator generates sortable unique event IDs in the ULID format:
+// a 48-bit millisecond timestamp followed by 80 random bits, encoded as 26 Crockford base32 characters.
+// IDs generated within the same millisecond increment the random part, so they sort in generation order.
+type EventIDGenerator struct {
+	mu         sync.Mutex
+	lastMillis uint64
+	lastRandom [10]byte
+}
+
+var defaultEventIDGenerator = &EventIDGenerator{}
+
+// NewEventID returns a new event ID from the default generator.
+func NewEventID() string {
+	return defaultEventIDGenerator.New()
+}
+
+// New returns a new event ID.
+func (g *EventIDGenerator) New() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	millis := uint64(time.Now().UnixMilli())
+	switch {
+	case millis > g.lastMillis:
+		_, _ = rand.Read(g.lastRandom[:])
+		g.lastMillis = millis
+	case incrementRandom(&g.lastRandom):
+		// same millisecond (or clock going backwards): keep the order by incrementing the random part
+		millis = g.lastMillis
+	default:
+		// the random part overflowed, borrow the next millisecond
+		g.lastMillis++
+		millis = g.lastMillis
+	}
+
+	var id [16]byte
+	id[0] = byte(millis >> 40)
+	id[1] = byte(millis >> 32)
+	id[2] = byte(millis >> 24)
+	id[3] = byte(millis >> 16)
+	id[4] = byte(millis >> 8)
+	id[5] = byte(millis)
+	copy(id[6:], g.lastRandom[:])
+	return encodeULID(id)
+}
+
+// incrementRandom increments the random part of an ID and reports whether it did not overflow.
+func incrementRandom(random *[10]byte) bool {
+	for i := len(random) - 1; i >= 0; i-- {
+		random[i]++
+		if random[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeULID encodes 128 bits as 26 Crockford base32 characters, most significant bits first.
+func encodeULID(id [16]byte) string {
+	var out [26]byte
+
+	// 130 bits of output for 128 bits of input: the first character holds only 3 bits
+	var acc uint64
+	bits := uint(2) // two leading zero bits
+	pos := 0
+	for _, b := range id {
+		acc = acc<<8 | uint64(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out[pos] = crockford[(acc>>bits)&0x1F]
+			pos++
+		}
+	}
+	return string(out[:])
+}
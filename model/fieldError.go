@@ -0,0 +1,46 @@
+// model/fieldError.go
+package commonModel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError describes a single offending field of an event.
+type FieldError struct {
+	Field  string // path of the field, e.g. "PID" or "Metadata.PID"
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+// FieldErrors collects every FieldError found in an event.
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		msgs = append(msgs, fieldErr.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the FieldErrors as a slice of errors so that errors.As can reach each FieldError.
+func (e FieldErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, fieldErr := range e {
+		errs = append(errs, fieldErr)
+	}
+	return errs
+}
+
+// WithPrefix returns a copy of the FieldErrors whose field paths start with prefix, e.g. "Metadata.".
+func (e FieldErrors) WithPrefix(prefix string) FieldErrors {
+	prefixed := make(FieldErrors, 0, len(e))
+	for _, fieldErr := range e {
+		prefixed = append(prefixed, &FieldError{Field: prefix + fieldErr.Field, Reason: fieldErr.Reason})
+	}
+	return prefixed
+}
@@ -0,0 +1,79 @@
+// model/host.go
+package commonModel
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// machineIDPaths are read in order, the dbus path is used by older distributions.
+	machineIDPaths = []string{"etc/machine-id", "var/lib/dbus/machine-id"}
+	bootIDPath     = "proc/sys/kernel/random/boot_id"
+	hostnamePath   = "proc/sys/kernel/hostname"
+)
+
+// HostIdentity identifies the host and the agent that produce events.
+type HostIdentity struct {
+	HostID       string // content of /etc/machine-id
+	Hostname     string // content of /proc/sys/kernel/hostname, or os.Hostname
+	BootID       string // content of /proc/sys/kernel/random/boot_id, changes on every boot
+	AgentVersion string // version of the agent, given by the caller
+}
+
+// LoadHostIdentity reads the identity of the running host.
+func LoadHostIdentity(agentVersion string) (HostIdentity, error) {
+	return LoadHostIdentityFrom("/", agentVersion)
+}
+
+// LoadHostIdentityFrom reads the identity of the host whose filesystem is mounted at root.
+// Every field that can be read is filled, and the errors of the others are joined.
+func LoadHostIdentityFrom(root, agentVersion string) (HostIdentity, error) {
+	var errs []error
+
+	identity := HostIdentity{AgentVersion: agentVersion}
+
+	for _, path := range machineIDPaths {
+		hostID, err := readIdentityFile(filepath.Join(root, path))
+		if err == nil {
+			identity.HostID = hostID
+			errs = nil
+			break
+		}
+		errs = append(errs, err)
+	}
+
+	bootID, err := readIdentityFile(filepath.Join(root, bootIDPath))
+	if err != nil {
+		errs = append(errs, err)
+	}
+	identity.BootID = bootID
+
+	identity.Hostname, err = readIdentityFile(filepath.Join(root, hostnamePath))
+	if err != nil {
+		if identity.Hostname, err = os.Hostname(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return identity, errors.Join(errs...)
+}
+
+// readIdentityFile returns the trimmed content of a single-line identity file.
+func readIdentityFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// StampHostIdentity copies the host identity into the header.
+func (h *CommonHeader) StampHostIdentity(identity HostIdentity) {
+	h.HostID = identity.HostID
+	h.Hostname = identity.Hostname
+	h.BootID = identity.BootID
+	h.AgentVersion = identity.AgentVersion
+}
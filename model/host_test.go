@@ -0,0 +1,63 @@
+package commonModel_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+)
+
+// writeFakeFile writes content to root/path, creating its directories.
+func writeFakeFile(t *testing.T, root, path, content string) {
+	t.Helper()
+	full := filepath.Join(root, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+}
+
+func TestLoadHostIdentity(t *testing.T) {
+	// The identity is read from a fake root filesystem and stamped on a header
+	root := t.TempDir()
+	writeFakeFile(t, root, "etc/machine-id", "4c4c4544004b4a10804cb7c04f4e3132\n")
+	writeFakeFile(t, root, "proc/sys/kernel/random/boot_id", "9a1b0c6e-2f35-4a8e-bb0c-6b1f3a0d7e21\n")
+	writeFakeFile(t, root, "proc/sys/kernel/hostname", "web-01\n")
+
+	identity, err := commonModel.LoadHostIdentityFrom(root, "1.4.0")
+	if err != nil {
+		t.Fatalf("Failed to load host identity: %v", err)
+	}
+	want := commonModel.HostIdentity{
+		HostID:       "4c4c4544004b4a10804cb7c04f4e3132",
+		Hostname:     "web-01",
+		BootID:       "9a1b0c6e-2f35-4a8e-bb0c-6b1f3a0d7e21",
+		AgentVersion: "1.4.0",
+	}
+	if identity != want {
+		t.Fatalf("Loaded host identity does not match: got %+v, want %+v", identity, want)
+	}
+
+	header := &commonModel.CommonHeader{}
+	header.StampHostIdentity(identity)
+	if header.HostID != want.HostID || header.AgentVersion != want.AgentVersion {
+		t.Fatalf("Stamped header does not match: %+v", header)
+	}
+}
+
+func TestLoadHostIdentityMissingFiles(t *testing.T) {
+	// Missing files are reported while the readable fields are still filled
+	root := t.TempDir()
+	writeFakeFile(t, root, "var/lib/dbus/machine-id", "abcdef\n")
+
+	identity, err := commonModel.LoadHostIdentityFrom(root, "1.4.0")
+	if err == nil {
+		t.Fatal("Expected an error due to missing boot_id, but got none")
+	}
+	if identity.HostID != "abcdef" || identity.Hostname == "" {
+		t.Fatalf("Readable fields were not filled: %+v", identity)
+	}
+}
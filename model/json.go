@@ -0,0 +1,111 @@
+// model/json.go
+package commonModel
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+const (
+	ErrUnknownEventCode = "unknown event code: %d"
+)
+
+// Allocator provides CommonModel objects for an EventCode, and takes back the objects
+// the decoders allocated but do not return because decoding failed.
+// eventPool.Pool satisfies this interface.
+type Allocator interface {
+	Allocate(eventCode EventCode) (*CommonModel, error)
+	Free(event any) error
+}
+
+// rawModel is a CommonModel whose Metadata is not decoded yet.
+type rawModel struct {
+	CommonHeader
+	Metadata json.RawMessage `json:"Metadata"`
+}
+
+// readRawModel decodes the header of a JSON encoded event and migrates it to CURRENT_SCHEMA_VERSION.
+func readRawModel(data []byte) (*rawModel, error) {
+	raw := &rawModel{}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return nil, err
+	}
+
+	// events written by an older schema version are migrated before decoding
+	if raw.SchemaVersion < CURRENT_SCHEMA_VERSION {
+		header, metadata, err := migrateRaw(raw.CommonHeader, raw.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		raw.CommonHeader, raw.Metadata = header, metadata
+	}
+	return raw, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It reads the EventCode first and decodes Metadata directly into the registered
+// metadata structure of that code (e.g. *eventModel.ProcessCreateMetadata).
+// If Metadata already holds a structure of the right type, as it does for objects
+// allocated from eventPool.Pool, it is reset and reused.
+// Events written by an older SchemaVersion are upgraded with the registered migrations.
+func (m *CommonModel) UnmarshalJSON(data []byte) error {
+	raw, err := readRawModel(data)
+	if err != nil {
+		return err
+	}
+	return m.decodeRawModel(raw)
+}
+
+// decodeRawModel fills the model with the header and the decoded metadata of raw.
+func (m *CommonModel) decodeRawModel(raw *rawModel) error {
+	metadata, err := m.resetMetadata(raw.EventCode)
+	if err != nil {
+		return err
+	}
+
+	if len(raw.Metadata) > 0 {
+		if err := json.Unmarshal(raw.Metadata, metadata); err != nil {
+			return err
+		}
+	}
+
+	m.CommonHeader = raw.CommonHeader
+	m.Metadata = metadata
+	return nil
+}
+
+// Unmarshal decodes a JSON encoded event into a CommonModel obtained from the allocator.
+// Only the header is read before allocation, so the metadata is decoded once into the allocated object.
+func Unmarshal(data []byte, allocator Allocator) (*CommonModel, error) {
+	raw, err := readRawModel(data)
+	if err != nil {
+		return nil, err
+	}
+
+	model, err := allocator.Allocate(raw.EventCode)
+	if err != nil {
+		return nil, err
+	}
+	if err = model.decodeRawModel(raw); err != nil {
+		// the allocated model is not returned, give it back
+		return nil, errors.Join(err, allocator.Free(model))
+	}
+	return model, nil
+}
+
+// resetMetadata returns the metadata structure to decode an event of the given code into.
+// The current metadata is reset and reused if it has the registered type, as it does for
+// objects allocated from eventPool.Pool; otherwise a new structure is allocated.
+func (m *CommonModel) resetMetadata(code EventCode) (any, error) {
+	registeredType, isExists := metadataType(code)
+	if !isExists {
+		return nil, unknownEventCodeError(code)
+	}
+	if m.Metadata != nil && reflect.TypeOf(m.Metadata) == registeredType {
+		reflect.ValueOf(m.Metadata).Elem().SetZero()
+		return m.Metadata, nil
+	}
+	metadata, _ := NewMetadata(code)
+	return metadata, nil
+}
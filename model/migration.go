@@ -0,0 +1,125 @@
+// model/migration.go
+package commonModel
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// CURRENT_SCHEMA_VERSION is the schema version of the event models in this package.
+// Events without a SchemaVersion were written before versioning and have version 0.
+const CURRENT_SCHEMA_VERSION uint32 = 1
+
+const (
+	ErrMigrationNotOlder  = "cannot register migration from schema version %d, current version is %d"
+	ErrDuplicateMigration = "migration from schema version %d for event code %d is already registered"
+	ErrMigrationFailed    = "failed to migrate event code %d from schema version %d: %w"
+)
+
+var (
+	ErrNilMigration        = errors.New("migration cannot be nil")
+	ErrNilMigrationWrapper = errors.New("cannot migrate nil CommonModelWrapper")
+)
+
+// MigrationFunc upgrades an event from one schema version to the next.
+// It may rename or convert metadata keys, and may change the EventCode to split a
+// former event type into several ones. The SchemaVersion is advanced by Migrate.
+// When migrating JSON encoded events, e.g. in CommonModel.UnmarshalJSON, metadata numbers are json.Number.
+// Producers may omit SchemaVersion, so a migration from version 0 must check that the event
+// has the legacy shape, e.g. by its former metadata keys, and leave other events untouched.
+type MigrationFunc func(wrapper *CommonModelWrapper) error
+
+// migrationKey identifies a migration by the version and the EventCode it applies to.
+type migrationKey struct {
+	fromVersion uint32
+	eventCode   EventCode
+}
+
+var (
+	migrationMu sync.RWMutex
+	migrations  = map[migrationKey]MigrationFunc{}
+)
+
+// RegisterMigration registers a migration of events with the given code from fromVersion to fromVersion+1.
+// The code is the EventCode as written by fromVersion.
+func RegisterMigration(fromVersion uint32, code EventCode, migrate MigrationFunc) error {
+	if fromVersion >= CURRENT_SCHEMA_VERSION {
+		return fmt.Errorf(ErrMigrationNotOlder, fromVersion, CURRENT_SCHEMA_VERSION)
+	}
+	if migrate == nil {
+		return ErrNilMigration
+	}
+
+	migrationMu.Lock()
+	defer migrationMu.Unlock()
+
+	key := migrationKey{fromVersion: fromVersion, eventCode: code}
+	if _, isExists := migrations[key]; isExists {
+		return fmt.Errorf(ErrDuplicateMigration, fromVersion, code)
+	}
+	migrations[key] = migrate
+	return nil
+}
+
+// Migrate upgrades the wrapper in place to CURRENT_SCHEMA_VERSION by applying the
+// registered migrations one version at a time.
+// Events written by a newer schema version are left untouched.
+func Migrate(wrapper *CommonModelWrapper) error {
+	if wrapper == nil {
+		return ErrNilMigrationWrapper
+	}
+
+	for wrapper.SchemaVersion < CURRENT_SCHEMA_VERSION {
+		migrationMu.RLock()
+		migrate, isExists := migrations[migrationKey{fromVersion: wrapper.SchemaVersion, eventCode: wrapper.EventCode}]
+		migrationMu.RUnlock()
+
+		if isExists {
+			if err := migrate(wrapper); err != nil {
+				return fmt.Errorf(ErrMigrationFailed, wrapper.EventCode, wrapper.SchemaVersion, err)
+			}
+		}
+		wrapper.SchemaVersion++
+	}
+	return nil
+}
+
+// RenameMetadataKey renames a metadata key of the wrapper.
+// Nothing happens if oldKey is absent or newKey is already present.
+func (w *CommonModelWrapper) RenameMetadataKey(oldKey, newKey string) {
+	value, isExists := w.Metadata[oldKey]
+	if !isExists {
+		return
+	}
+	if _, isExists = w.Metadata[newKey]; isExists {
+		return
+	}
+	w.Metadata[newKey] = value
+	delete(w.Metadata, oldKey)
+}
+
+// migrateRaw migrates a JSON encoded metadata written by an older schema version.
+// It returns the migrated header and metadata.
+func migrateRaw(header CommonHeader, metadata json.RawMessage) (CommonHeader, json.RawMessage, error) {
+	wrapper := &CommonModelWrapper{CommonHeader: header}
+	if len(metadata) > 0 {
+		// numbers are kept as json.Number, float64 would round integers above 2^53
+		dec := json.NewDecoder(bytes.NewReader(metadata))
+		dec.UseNumber()
+		if err := dec.Decode(&wrapper.Metadata); err != nil {
+			return header, nil, err
+		}
+	}
+	if err := Migrate(wrapper); err != nil {
+		return header, nil, err
+	}
+
+	migrated, err := json.Marshal(wrapper.Metadata)
+	if err != nil {
+		return header, nil, err
+	}
+	return wrapper.CommonHeader, migrated, nil
+}
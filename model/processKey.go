@@ -0,0 +1,16 @@
+// model/processKey.go
+package commonModel
+
+import (
+	"strconv"
+	"time"
+)
+
+// ProcessKey identifies a process instance across PID reuse.
+// It is made of the PID and the start time of the process in Unix milliseconds, e.g. "1234@1735689605000".
+type ProcessKey string
+
+// NewProcessKey returns the ProcessKey of the process with the given PID started at startTime.
+func NewProcessKey(pid int64, startTime time.Time) ProcessKey {
+	return ProcessKey(strconv.FormatInt(pid, 10) + "@" + strconv.FormatInt(startTime.UnixMilli(), 10))
+}
@@ -0,0 +1,191 @@
+// model/registry.go
+package commonModel
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+
+	stateConstants "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+// USER_EVENT_CODE_MIN is the first EventCode available to third-party event types.
+// Codes below it are reserved for the built-in event types of polvo.
+const USER_EVENT_CODE_MIN EventCode = 1000
+
+const (
+	ErrReservedEventCode   = "event code %d is reserved, third-party event codes start at %d"
+	ErrDuplicateEventCode  = "event code %d is already registered as '%s'"
+	ErrDuplicateEventName  = "event name '%s' is already registered with code %d"
+	ErrBuiltinUnregistered = "unknown event code: %d (%s): built-in event types are registered by importing " + EVENT_MODEL_PACKAGE
+	ErrInvalidMetadata     = "metadata constructor of event code %d must return a non-nil pointer to a struct, got %T"
+)
+
+// EVENT_MODEL_PACKAGE is the package registering the built-in event types when imported.
+// commonModel cannot register them itself, since their metadata structures are defined there.
+const EVENT_MODEL_PACKAGE = "github.com/enki-polvo/polvo-logger/model/event"
+
+var (
+	ErrEmptyEventName   = errors.New("event name cannot be empty")
+	ErrNilMetadataMaker = errors.New("metadata constructor cannot be nil")
+)
+
+// eventType holds the registration of a single event type.
+type eventType struct {
+	name         string
+	newMetadata  func() any
+	metadataType reflect.Type // the pointer type returned by newMetadata
+}
+
+var (
+	registryMu    sync.RWMutex
+	registry      = map[EventCode]eventType{}
+	registryNames = map[string]EventCode{}
+)
+
+// builtinNames maps the names of the built-in event types to their codes, registered or not.
+var builtinNames = func() map[string]EventCode {
+	names := make(map[string]EventCode, len(eventCodes))
+	for _, code := range eventCodes {
+		names[code.String()] = code
+	}
+	return names
+}()
+
+// RegisterEventType registers an event type, so that the pool, the decoders and
+// EventCode.String() know about it.
+// newMetadata must return a non-nil pointer to a new metadata structure, e.g. &eventModel.ProcessCreateMetadata{};
+// it is called once at registration to check it.
+// Each code and each name can be registered once. Codes below USER_EVENT_CODE_MIN are reserved
+// for the built-in event types, which register themselves when the eventModel package is imported.
+func RegisterEventType(code EventCode, name string, newMetadata func() any) error {
+	if code < USER_EVENT_CODE_MIN && !slices.Contains(eventCodes, code) {
+		return fmt.Errorf(ErrReservedEventCode, code, USER_EVENT_CODE_MIN)
+	}
+	if name == "" {
+		return ErrEmptyEventName
+	}
+	if newMetadata == nil {
+		return ErrNilMetadataMaker
+	}
+	// the decoders reset and fill the metadata through its pointer
+	value := newMetadata()
+	metadata := reflect.ValueOf(value)
+	if metadata.Kind() != reflect.Pointer || metadata.IsNil() || metadata.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf(ErrInvalidMetadata, code, value)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if registered, isExists := registry[code]; isExists {
+		return fmt.Errorf(ErrDuplicateEventCode, code, registered.name)
+	}
+	if registeredCode, isExists := registryNames[name]; isExists {
+		return fmt.Errorf(ErrDuplicateEventName, name, registeredCode)
+	}
+	// third-party names must not shadow the built-in names either
+	if builtin, isExists := builtinNames[name]; isExists && code >= USER_EVENT_CODE_MIN {
+		return fmt.Errorf(ErrDuplicateEventName, name, builtin)
+	}
+
+	registry[code] = eventType{name: name, newMetadata: newMetadata, metadataType: metadata.Type()}
+	registryNames[name] = code
+	return nil
+}
+
+// RegisteredEventCodes returns every registered EventCode in ascending order.
+// The built-in event types are only registered if EVENT_MODEL_PACKAGE is imported.
+func RegisteredEventCodes() []EventCode {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	codes := make([]EventCode, 0, len(registry))
+	for code := range registry {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	return codes
+}
+
+// NewMetadata returns a new metadata structure for the given code.
+// It returns false if the code is not registered.
+func NewMetadata(code EventCode) (any, bool) {
+	registryMu.RLock()
+	registered, isExists := registry[code]
+	registryMu.RUnlock()
+
+	if !isExists {
+		return nil, false
+	}
+	return registered.newMetadata(), true
+}
+
+// unknownEventCodeError returns the error of decoding an event whose code is not registered.
+// For built-in codes, it names the package whose import registers them.
+func unknownEventCodeError(code EventCode) error {
+	if slices.Contains(eventCodes, code) {
+		return fmt.Errorf(ErrBuiltinUnregistered, code, code)
+	}
+	return fmt.Errorf(ErrUnknownEventCode, code)
+}
+
+// registeredName returns the name of a registered EventCode, or an empty string.
+func registeredName(code EventCode) string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return registry[code].name
+}
+
+// metadataType returns the pointer type of the metadata registered for code.
+func metadataType(code EventCode) (reflect.Type, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	registered, isExists := registry[code]
+	return registered.metadataType, isExists
+}
+
+// lookupEventCode parses the name or the number of a built-in or registered EventCode.
+func lookupEventCode(text string) (EventCode, bool) {
+	// text is only parsed as a number if it looks like one, since a failed Atoi allocates its error
+	if looksNumeric(text) {
+		if number, err := strconv.Atoi(text); err == nil {
+			return EventCode(number), isKnownEventCode(EventCode(number))
+		}
+	}
+
+	if code, isExists := builtinNames[text]; isExists {
+		return code, true
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	code, isExists := registryNames[text]
+	return code, isExists
+}
+
+// isKnownEventCode reports whether code is a built-in or a registered EventCode.
+func isKnownEventCode(code EventCode) bool {
+	if slices.Contains(eventCodes, code) {
+		return true
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	_, isExists := registry[code]
+	return isExists
+}
+
+// looksNumeric reports whether text starts like a decimal number.
+func looksNumeric(text string) bool {
+	return text != "" && (text[0] == '-' || text[0] == '+' || ('0' <= text[0] && text[0] <= '9'))
+}
+
+// parseEventCodeError returns the error of parsing an unknown EventCode, like stateConstants.ParseEnum.
+func parseEventCodeError(text string) error {
+	return fmt.Errorf(stateConstants.ErrInvalidEnumText, EventCode(0), text)
+}
@@ -0,0 +1,91 @@
+// model/ruleMatch.go
+package commonModel
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	stateConstants "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+// RuleMatch records a detection rule matched by an event or an entity.
+type RuleMatch struct {
+	RuleID          string                  `json:"RuleID"`                    // example: "5f0e6d2a-proc-creation-lnx-susp-curl"
+	Name            string                  `json:"Name,omitempty"`            // example: "Suspicious curl download"
+	Severity        stateConstants.Severity `json:"Severity,omitempty"`        // example: "HIGH"
+	MitreTechniques []string                `json:"MitreTechniques,omitempty"` // example: ["T1105", "T1059.004"]
+	MatchedAt       time.Time               `json:"MatchedAt"`                 // example: "2025-01-01T00:00:00Z"
+	EventID         string                  `json:"EventID,omitempty"`         // example: "01JXB4T5K3Z8Q2W9E7R6Y5V4N3" (the event triggering the match)
+}
+
+// RuleMatches is the list of rules matched by an event or an entity.
+// It is encoded as a JSON array, and decoded from either an array or the legacy string form.
+type RuleMatches []RuleMatch
+
+// ParseLegacyRuleIDs parses the legacy string form of matched rules, a list of rule IDs
+// separated by commas, semicolons, pipes or white space (e.g. "rule1,rule2").
+// The returned matches only have their RuleID set.
+func ParseLegacyRuleIDs(text string) RuleMatches {
+	ids := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ';' || r == '|' || r == ' ' || r == '\t' || r == '\n'
+	})
+
+	var matches RuleMatches
+	for _, id := range ids {
+		matches = append(matches, RuleMatch{RuleID: id})
+	}
+	return matches
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both an array of rule matches
+// and the legacy string of rule IDs.
+func (m *RuleMatches) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var legacy string
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return err
+		}
+		*m = ParseLegacyRuleIDs(legacy)
+		return nil
+	}
+
+	var matches []RuleMatch
+	if err := json.Unmarshal(data, &matches); err != nil {
+		return err
+	}
+	*m = matches
+	return nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for the legacy string form,
+// so that metadata decoders using text hooks accept it as well.
+func (m *RuleMatches) UnmarshalText(text []byte) error {
+	*m = ParseLegacyRuleIDs(string(text))
+	return nil
+}
+
+// IDs returns the rule IDs of the matches.
+func (m RuleMatches) IDs() []string {
+	ids := make([]string, 0, len(m))
+	for _, match := range m {
+		ids = append(ids, match.RuleID)
+	}
+	return ids
+}
+
+// Clone returns a deep copy of the matches.
+func (m RuleMatches) Clone() RuleMatches {
+	if m == nil {
+		return nil
+	}
+	clone := make(RuleMatches, len(m))
+	for i, match := range m {
+		clone[i] = match
+		clone[i].MitreTechniques = slices.Clone(match.MitreTechniques)
+	}
+	return clone
+}
@@ -0,0 +1,61 @@
+package commonModel_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	stateConstants "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+func TestRuleMatchesLegacyString(t *testing.T) {
+	// The legacy string of rule IDs decodes into rule matches with only their RuleID set
+	data := `{"EventCode":4,"EventName":"TcpEvent","Source":"eBPF","MatchedRuleIDs":"rule1, rule2;rule3","Metadata":{"PID":1234,"Op":1}}`
+
+	cm := &commonModel.CommonModel{}
+	if err := json.Unmarshal([]byte(data), cm); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	if got, want := cm.MatchedRuleIDs.IDs(), []string{"rule1", "rule2", "rule3"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Decoded rule IDs do not match: got %v, want %v", got, want)
+	}
+}
+
+func TestRuleMatchesRoundTrip(t *testing.T) {
+	// Structured rule matches survive the JSON and binary encodings of an event
+	origin := binaryTestEvents()[0]
+	origin.MatchedRuleIDs = commonModel.RuleMatches{{
+		RuleID:          "5f0e6d2a-proc-creation-lnx-susp-curl",
+		Name:            "Suspicious curl download",
+		Severity:        stateConstants.SEVERITY_HIGH,
+		MitreTechniques: []string{"T1105", "T1059.004"},
+		MatchedAt:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EventID:         "01JXB4T5K3Z8Q2W9E7R6Y5V4N3",
+	}}
+
+	data, err := json.Marshal(origin)
+	if err != nil {
+		t.Fatalf("Failed to marshal JSON: %v", err)
+	}
+	fromJSON := &commonModel.CommonModel{}
+	if err = json.Unmarshal(data, fromJSON); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+
+	data, err = origin.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal binary: %v", err)
+	}
+	fromBinary := &commonModel.CommonModel{}
+	if err = fromBinary.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Failed to unmarshal binary: %v", err)
+	}
+
+	for _, decoded := range []*commonModel.CommonModel{fromJSON, fromBinary} {
+		if !reflect.DeepEqual(decoded.MatchedRuleIDs, origin.MatchedRuleIDs) {
+			t.Fatalf("Decoded rule matches do not match:\n got: %+v\nwant: %+v", decoded.MatchedRuleIDs, origin.MatchedRuleIDs)
+		}
+	}
+}
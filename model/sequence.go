@@ -0,0 +1,157 @@
+// model/sequence.go
+package commonModel
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+)
+
+// Sequencer stamps events with a unique EventID and a sequence number per Source.
+// Sequence numbers start at 1 and increase by one for every event of the same Source,
+// so consumers can detect dropped events with a GapDetector.
+type Sequencer struct {
+	mu   sync.Mutex
+	last map[string]uint64
+	ids  EventIDGenerator
+}
+
+// NewSequencer returns a Sequencer whose sequences start at 1.
+func NewSequencer() *Sequencer {
+	return &Sequencer{last: map[string]uint64{}}
+}
+
+// Next returns the next sequence number of the source.
+func (s *Sequencer) Next(source string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.last[source]++
+	return s.last[source]
+}
+
+// Stamp sets a new EventID and the next sequence number of the header's Source.
+// The Source must be set before stamping.
+func (s *Sequencer) Stamp(h *CommonHeader) {
+	h.EventID = s.ids.New()
+	h.Seq = s.Next(h.Source)
+}
+
+// SeqGap is a range of sequence numbers of a Source that were not observed.
+type SeqGap struct {
+	Source string
+	From   uint64 // first missing sequence number
+	To     uint64 // last missing sequence number
+}
+
+// GapDetector tracks the sequence numbers observed per Source and reports the missing ranges.
+// Events arriving late fill the gaps they belong to.
+// A Source restarting its sequence, e.g. after an agent restart, is detected by a new BootID
+// or by sequence number 1 outside of its gaps; its sequence and gaps then start over.
+type GapDetector struct {
+	mu    sync.Mutex
+	last  map[string]uint64
+	gaps  map[string][]SeqGap
+	boots map[string]string // last BootID per Source
+}
+
+// NewGapDetector returns an empty GapDetector.
+func NewGapDetector() *GapDetector {
+	return &GapDetector{
+		last:  map[string]uint64{},
+		gaps:  map[string][]SeqGap{},
+		boots: map[string]string{},
+	}
+}
+
+// Observe records the sequence number of an event. Events without a sequence number are ignored.
+// It returns the gap opened by this event, if any.
+func (d *GapDetector) Observe(h *CommonHeader) (gap SeqGap, isGap bool) {
+	if h.Seq == 0 {
+		return gap, false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.isRestart(h) {
+		delete(d.last, h.Source)
+		delete(d.gaps, h.Source)
+	}
+	if h.BootID != "" {
+		d.boots[h.Source] = h.BootID
+	}
+
+	last := d.last[h.Source]
+	switch {
+	case h.Seq == last+1:
+		d.last[h.Source] = h.Seq
+	case h.Seq > last+1:
+		gap = SeqGap{Source: h.Source, From: last + 1, To: h.Seq - 1}
+		d.gaps[h.Source] = append(d.gaps[h.Source], gap)
+		d.last[h.Source] = h.Seq
+		return gap, true
+	default:
+		// a late or duplicated event
+		d.fill(h.Source, h.Seq)
+	}
+	return gap, false
+}
+
+// isRestart reports whether the event starts a new sequence of its Source.
+func (d *GapDetector) isRestart(h *CommonHeader) bool {
+	if _, isExists := d.last[h.Source]; !isExists {
+		return false
+	}
+	if boot := d.boots[h.Source]; boot != "" && h.BootID != "" && boot != h.BootID {
+		return true
+	}
+	if h.Seq != 1 {
+		return false
+	}
+	// sequence number 1 is a late event if the first events were missing
+	for _, gap := range d.gaps[h.Source] {
+		if gap.From == 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// fill removes seq from the gaps of the source, splitting the gap it belongs to.
+func (d *GapDetector) fill(source string, seq uint64) {
+	gaps := d.gaps[source]
+	for i, gap := range gaps {
+		if seq < gap.From || seq > gap.To {
+			continue
+		}
+
+		var remaining []SeqGap
+		if seq > gap.From {
+			remaining = append(remaining, SeqGap{Source: source, From: gap.From, To: seq - 1})
+		}
+		if seq < gap.To {
+			remaining = append(remaining, SeqGap{Source: source, From: seq + 1, To: gap.To})
+		}
+		d.gaps[source] = slices.Replace(gaps, i, i+1, remaining...)
+		return
+	}
+}
+
+// Gaps returns the missing sequence ranges of every source, sorted by source and range.
+func (d *GapDetector) Gaps() []SeqGap {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var gaps []SeqGap
+	for _, sourceGaps := range d.gaps {
+		gaps = append(gaps, sourceGaps...)
+	}
+	slices.SortFunc(gaps, func(a, b SeqGap) int {
+		if c := cmp.Compare(a.Source, b.Source); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.From, b.From)
+	})
+	return gaps
+}
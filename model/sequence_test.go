@@ -0,0 +1,124 @@
+package commonModel_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+)
+
+func TestNewEventIDSortable(t *testing.T) {
+	// Event IDs are 26 characters long, unique and sorted in generation order
+	ids := make([]string, 0, 10000)
+	for i := 0; i < 10000; i++ {
+		ids = append(ids, commonModel.NewEventID())
+	}
+
+	for i, id := range ids {
+		if len(id) != 26 {
+			t.Fatalf("Event ID has unexpected length: %v", id)
+		}
+		if i > 0 && id <= ids[i-1] {
+			t.Fatalf("Event IDs are not strictly increasing: %v after %v", id, ids[i-1])
+		}
+	}
+}
+
+func TestSequencerPerSource(t *testing.T) {
+	// Every source has its own sequence, also when stamped concurrently
+	sequencer := commonModel.NewSequencer()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seqs := map[string][]uint64{}
+	for _, source := range []string{"eBPF", "libpcap"} {
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					header := &commonModel.CommonHeader{Source: source}
+					sequencer.Stamp(header)
+					mu.Lock()
+					seqs[source] = append(seqs[source], header.Seq)
+					mu.Unlock()
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	for source, got := range seqs {
+		slices.Sort(got)
+		for i, seq := range got {
+			if seq != uint64(i+1) {
+				t.Fatalf("Sequence of %v is not contiguous at %d: got %v", source, i, seq)
+			}
+		}
+	}
+}
+
+func TestGapDetector(t *testing.T) {
+	// Missing ranges are reported per source and late events fill them
+	detector := commonModel.NewGapDetector()
+	observe := func(source string, seqs ...uint64) {
+		for _, seq := range seqs {
+			detector.Observe(&commonModel.CommonHeader{Source: source, Seq: seq})
+		}
+	}
+
+	observe("eBPF", 1, 2, 6, 7, 10)
+	observe("libpcap", 1, 3)
+	// a late event in the middle of a gap splits it
+	observe("eBPF", 4)
+
+	want := []commonModel.SeqGap{
+		{Source: "eBPF", From: 3, To: 3},
+		{Source: "eBPF", From: 5, To: 5},
+		{Source: "eBPF", From: 8, To: 9},
+		{Source: "libpcap", From: 2, To: 2},
+	}
+	if got := detector.Gaps(); !slices.Equal(got, want) {
+		t.Fatalf("Reported gaps do not match:\n got: %v\nwant: %v", got, want)
+	}
+
+	gap, isGap := detector.Observe(&commonModel.CommonHeader{Source: "libpcap", Seq: 10})
+	if !isGap || gap.From != 4 || gap.To != 9 {
+		t.Fatalf("Observe did not report the new gap: %v, %v", gap, isGap)
+	}
+}
+
+func TestGapDetectorRestart(t *testing.T) {
+	// A source restarting its sequence starts over instead of filling old gaps
+	detector := commonModel.NewGapDetector()
+	observe := func(source, bootID string, seqs ...uint64) {
+		for _, seq := range seqs {
+			detector.Observe(&commonModel.CommonHeader{Source: source, BootID: bootID, Seq: seq})
+		}
+	}
+
+	observe("eBPF", "", 1, 2, 5, 6)
+	// the agent restarts: the old gap is forgotten and the new sequence is tracked
+	observe("eBPF", "", 1, 2, 4)
+	want := []commonModel.SeqGap{{Source: "eBPF", From: 3, To: 3}}
+	if got := detector.Gaps(); !slices.Equal(got, want) {
+		t.Fatalf("Reported gaps after a restart do not match:\n got: %v\nwant: %v", got, want)
+	}
+
+	// a late first event fills its gap instead of restarting the sequence
+	observe("libpcap", "", 3, 4)
+	observe("libpcap", "", 1)
+	want = append(want, commonModel.SeqGap{Source: "libpcap", From: 2, To: 2})
+	if got := detector.Gaps(); !slices.Equal(got, want) {
+		t.Fatalf("Reported gaps after a late first event do not match:\n got: %v\nwant: %v", got, want)
+	}
+
+	// a new boot restarts the sequence even if the first events were lost
+	observe("audit", "boot-1", 1, 2, 3, 8)
+	observe("audit", "boot-2", 3, 4)
+	want = append([]commonModel.SeqGap{{Source: "audit", From: 1, To: 2}}, want...)
+	if got := detector.Gaps(); !slices.Equal(got, want) {
+		t.Fatalf("Reported gaps after a new boot do not match:\n got: %v\nwant: %v", got, want)
+	}
+}
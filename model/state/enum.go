@@ -0,0 +1,69 @@
+// model/state/enum.go
+package stateConstants
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+const (
+	ErrInvalidEnumText = "invalid %T value: %q"
+)
+
+// Enum is the constraint satisfied by the integer enums of the polvo models.
+type Enum interface {
+	~int
+	fmt.Stringer
+}
+
+// ParseEnum parses a name or a decimal number into one of the given values.
+// Both must belong to values, so that integer-encoded logs keep decoding
+// while values outside of the enum are rejected.
+func ParseEnum[T Enum](text string, values []T) (T, error) {
+	number, err := strconv.Atoi(text)
+	for _, value := range values {
+		if (err == nil && int(value) == number) || (err != nil && value.String() == text) {
+			return value, nil
+		}
+	}
+	var zero T
+	return zero, fmt.Errorf(ErrInvalidEnumText, zero, text)
+}
+
+// MarshalEnumText encodes value as its name, e.g. "TCP_CONNECT".
+// Values without a name are encoded as their number.
+func MarshalEnumText[T Enum](value T) ([]byte, error) {
+	if name := value.String(); name != "" {
+		return []byte(name), nil
+	}
+	return strconv.AppendInt(nil, int64(value), 10), nil
+}
+
+// MarshalEnumJSON encodes value as a JSON string holding its name.
+// Values without a name are encoded as a JSON number.
+func MarshalEnumJSON[T Enum](value T) ([]byte, error) {
+	if name := value.String(); name != "" {
+		return json.Marshal(name)
+	}
+	return strconv.AppendInt(nil, int64(value), 10), nil
+}
+
+// UnmarshalEnumJSON decodes a JSON string or JSON number into dest using its UnmarshalText.
+// A JSON null leaves dest unchanged.
+func UnmarshalEnumJSON(data []byte, dest encoding.TextUnmarshaler) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		return dest.UnmarshalText([]byte(text))
+	}
+	return dest.UnmarshalText(data)
+}
@@ -29,6 +29,38 @@ func (s State) String() string {
 	}
 }
 
+var states = []State{CREATED, MODIFIED, REUP}
+
+// ParseState parses a State from its name (e.g. "MODIFIED") or its number.
+func ParseState(text string) (State, error) {
+	return ParseEnum(text, states)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s State) MarshalText() ([]byte, error) {
+	return MarshalEnumText(s)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts both names and numbers.
+func (s *State) UnmarshalText(text []byte) error {
+	value, err := ParseState(string(text))
+	if err != nil {
+		return err
+	}
+	*s = value
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s State) MarshalJSON() ([]byte, error) {
+	return MarshalEnumJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both strings and numbers.
+func (s *State) UnmarshalJSON(data []byte) error {
+	return UnmarshalEnumJSON(data, s)
+}
+
 // FileOpenPurposeOp defines the purposes of a specific file open operation
 type FileOpenPurposeOp int
 
@@ -58,6 +90,38 @@ func (f FileOpenPurposeOp) String() string {
 	}
 }
 
+var fileOpenPurposeOps = []FileOpenPurposeOp{FILE_OPEN_TO_UNSET, FILE_OPEN_TO_READ, FILE_OPEN_TO_WRITE, FILE_OPEN_TO_OTHER}
+
+// ParseFileOpenPurposeOp parses a FileOpenPurposeOp from its name (e.g. "FILE_OPEN_TO_READ") or its number.
+func ParseFileOpenPurposeOp(text string) (FileOpenPurposeOp, error) {
+	return ParseEnum(text, fileOpenPurposeOps)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (f FileOpenPurposeOp) MarshalText() ([]byte, error) {
+	return MarshalEnumText(f)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts both names and numbers.
+func (f *FileOpenPurposeOp) UnmarshalText(text []byte) error {
+	value, err := ParseFileOpenPurposeOp(string(text))
+	if err != nil {
+		return err
+	}
+	*f = value
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (f FileOpenPurposeOp) MarshalJSON() ([]byte, error) {
+	return MarshalEnumJSON(f)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both strings and numbers.
+func (f *FileOpenPurposeOp) UnmarshalJSON(data []byte) error {
+	return UnmarshalEnumJSON(data, f)
+}
+
 // TcpOp defines the TCP operation types.
 type TcpOp int
 
@@ -86,3 +150,99 @@ func (t TcpOp) String() string {
 		return ""
 	}
 }
+
+var tcpOps = []TcpOp{TCP_OP_UNSET, TCP_CONNECT, TCP_DISCONNECT, TCP_ACCEPT}
+
+// ParseTcpOp parses a TcpOp from its name (e.g. "TCP_CONNECT") or its number.
+func ParseTcpOp(text string) (TcpOp, error) {
+	return ParseEnum(text, tcpOps)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t TcpOp) MarshalText() ([]byte, error) {
+	return MarshalEnumText(t)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts both names and numbers.
+func (t *TcpOp) UnmarshalText(text []byte) error {
+	value, err := ParseTcpOp(string(text))
+	if err != nil {
+		return err
+	}
+	*t = value
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t TcpOp) MarshalJSON() ([]byte, error) {
+	return MarshalEnumJSON(t)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both strings and numbers.
+func (t *TcpOp) UnmarshalJSON(data []byte) error {
+	return UnmarshalEnumJSON(data, t)
+}
+
+// Severity defines the severity of a matched detection rule, following the Sigma rule levels.
+type Severity int
+
+const (
+	// default value for Severity
+	SEVERITY_UNSET Severity = iota
+	SEVERITY_INFORMATIONAL
+	SEVERITY_LOW
+	SEVERITY_MEDIUM
+	SEVERITY_HIGH
+	SEVERITY_CRITICAL
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SEVERITY_UNSET:
+		return "UNSET"
+	case SEVERITY_INFORMATIONAL:
+		return "INFORMATIONAL"
+	case SEVERITY_LOW:
+		return "LOW"
+	case SEVERITY_MEDIUM:
+		return "MEDIUM"
+	case SEVERITY_HIGH:
+		return "HIGH"
+	case SEVERITY_CRITICAL:
+		return "CRITICAL"
+	default:
+		return ""
+	}
+}
+
+var severities = []Severity{SEVERITY_UNSET, SEVERITY_INFORMATIONAL, SEVERITY_LOW, SEVERITY_MEDIUM, SEVERITY_HIGH, SEVERITY_CRITICAL}
+
+// ParseSeverity parses a Severity from its name (e.g. "HIGH") or its number.
+func ParseSeverity(text string) (Severity, error) {
+	return ParseEnum(text, severities)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Severity) MarshalText() ([]byte, error) {
+	return MarshalEnumText(s)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts both names and numbers.
+func (s *Severity) UnmarshalText(text []byte) error {
+	value, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = value
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return MarshalEnumJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both strings and numbers.
+func (s *Severity) UnmarshalJSON(data []byte) error {
+	return UnmarshalEnumJSON(data, s)
+}
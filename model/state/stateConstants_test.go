@@ -0,0 +1,70 @@
+package stateConstants_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	state "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+// Test parsing of enums
+// Test that both names and numbers are accepted, and unknown names are rejected
+func TestParseEnum(t *testing.T) {
+	op, err := state.ParseTcpOp("TCP_ACCEPT")
+	if err != nil || op != state.TCP_ACCEPT {
+		t.Fatalf("Failed to parse TcpOp by name: got %v, %v", op, err)
+	}
+
+	op, err = state.ParseTcpOp("2")
+	if err != nil || op != state.TCP_DISCONNECT {
+		t.Fatalf("Failed to parse TcpOp by number: got %v, %v", op, err)
+	}
+
+	_, err = state.ParseFileOpenPurposeOp("FILE_OPEN_TO_EXECUTE")
+	if err == nil {
+		t.Fatal("Expected error when parsing unknown name, but got none")
+	}
+
+	_, err = state.ParseTcpOp("99")
+	if err == nil {
+		t.Fatal("Expected error when parsing an out-of-range number, but got none")
+	}
+}
+
+// Test JSON and text encoding of enums
+// Test that enums are written as names, values without a name as numbers, and decoding accepts both
+func TestEnumJSONEncoding(t *testing.T) {
+	b, err := json.Marshal(state.REUP)
+	if err != nil || string(b) != `"REUP"` {
+		t.Fatalf("Unexpected name encoding: got %s, %v", b, err)
+	}
+	b, err = state.TCP_ACCEPT.MarshalText()
+	if err != nil || string(b) != "TCP_ACCEPT" {
+		t.Fatalf("Unexpected text encoding: got %s, %v", b, err)
+	}
+	b, err = json.Marshal(state.TcpOp(99))
+	if err != nil || string(b) != "99" {
+		t.Fatalf("Unexpected encoding of a value without name: got %s, %v", b, err)
+	}
+
+	severity := state.SEVERITY_HIGH
+	b, err = json.Marshal(struct {
+		Ops     []state.TcpOp   `json:"Ops"`
+		Pointer *state.Severity `json:"Pointer"`
+	}{Ops: []state.TcpOp{state.TCP_CONNECT}, Pointer: &severity})
+	if err != nil || string(b) != `{"Ops":["TCP_CONNECT"],"Pointer":"HIGH"}` {
+		t.Fatalf("Unexpected nested encoding: got %s, %v", b, err)
+	}
+
+	var decoded struct {
+		Old state.FileOpenPurposeOp
+		New state.FileOpenPurposeOp
+	}
+	err = json.Unmarshal([]byte(`{"Old":2,"New":"FILE_OPEN_TO_WRITE"}`), &decoded)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	if decoded.Old != state.FILE_OPEN_TO_WRITE || decoded.New != state.FILE_OPEN_TO_WRITE {
+		t.Fatalf("Decoded enums do not match: %+v", decoded)
+	}
+}
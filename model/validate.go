@@ -0,0 +1,58 @@
+// model/validate.go
+package commonModel
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+const (
+	ErrFieldUnregisteredCode = "is not a registered event code"
+	ErrFieldMetadataNil      = "must not be nil"
+	ErrFieldMetadataType     = "expected %v for %s, got %T"
+)
+
+// Validator is implemented by metadata structures that can check their own values,
+// e.g. *eventModel.TcpMetadata.
+type Validator interface {
+	Validate() error
+}
+
+// Validate checks that the EventCode is registered, that Metadata has the structure
+// registered for it, and that the metadata itself is valid.
+// The returned error is a FieldErrors whose metadata fields are prefixed with "Metadata.".
+func (m *CommonModel) Validate() error {
+	var fieldErrs FieldErrors
+
+	expected, isExists := metadataType(m.EventCode)
+	switch {
+	case !isExists:
+		fieldErrs = append(fieldErrs, &FieldError{Field: "EventCode", Reason: ErrFieldUnregisteredCode})
+	case m.Metadata == nil:
+		fieldErrs = append(fieldErrs, &FieldError{Field: "Metadata", Reason: ErrFieldMetadataNil})
+	case reflect.TypeOf(m.Metadata) != expected:
+		fieldErrs = append(fieldErrs, &FieldError{
+			Field:  "Metadata",
+			Reason: fmt.Sprintf(ErrFieldMetadataType, expected, m.EventCode.String(), m.Metadata),
+		})
+	default:
+		validator, ok := m.Metadata.(Validator)
+		if !ok {
+			break
+		}
+		if err := validator.Validate(); err != nil {
+			var metadataErrs FieldErrors
+			if errors.As(err, &metadataErrs) {
+				fieldErrs = append(fieldErrs, metadataErrs.WithPrefix("Metadata.")...)
+			} else {
+				fieldErrs = append(fieldErrs, &FieldError{Field: "Metadata", Reason: err.Error()})
+			}
+		}
+	}
+
+	if len(fieldErrs) == 0 {
+		return nil
+	}
+	return fieldErrs
+}
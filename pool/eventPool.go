@@ -8,7 +8,6 @@ import (
 
 	model "github.com/enki-polvo/polvo-logger/model"
 	eventModel "github.com/enki-polvo/polvo-logger/model/event"
-	stateConstants "github.com/enki-polvo/polvo-logger/model/state"
 )
 
 const (
@@ -17,65 +16,18 @@ const (
 	ErrInvalidTypeAssertionInPool = "invalid type assertion for event pool"
 )
 
-var (
-	modelMapper = map[model.EventCode]func() any{
-		model.PROC_CREATE: func() any {
-			obj := &model.CommonModel{}
-			obj.CommonHeader.EventCode = model.PROC_CREATE
-			obj.CommonHeader.EventName = model.PROC_CREATE.String()
-			obj.Metadata = &eventModel.ProcessCreateMetadata{}
-			return obj
-		},
-		model.PROC_TERMINATE: func() any {
-			obj := &model.CommonModel{}
-			obj.CommonHeader.EventCode = model.PROC_TERMINATE
-			obj.CommonHeader.EventName = model.PROC_TERMINATE.String()
-			obj.Metadata = &eventModel.ProcessTerminateMetadata{}
-			return obj
-		},
-		model.PROC_BASH_READLINE: func() any {
-			obj := &model.CommonModel{}
-			obj.CommonHeader.EventCode = model.PROC_BASH_READLINE
-			obj.CommonHeader.EventName = model.PROC_BASH_READLINE.String()
-			obj.Metadata = &eventModel.BashReadlineMetadata{}
-			return obj
-		},
-		model.PROC_SERVICE: func() any {
-			obj := &model.CommonModel{}
-			obj.CommonHeader.EventCode = model.PROC_SERVICE
-			obj.CommonHeader.EventName = model.PROC_SERVICE.String()
-			obj.Metadata = &eventModel.ServiceMetadata{}
-			return obj
-		},
-		model.TCP_EVENT: func() any {
-			obj := &model.CommonModel{}
-			obj.CommonHeader.EventCode = model.TCP_EVENT
-			obj.CommonHeader.EventName = model.TCP_EVENT.String()
-			// Initialize the metadata Opcode for TCP events
-			obj.Metadata = &eventModel.TcpMetadata{
-				Op: stateConstants.TCP_OP_UNSET, // default value
-			}
-			return obj
-		},
-		model.FILE_OPEN_EVENT: func() any {
-			obj := &model.CommonModel{}
-			obj.CommonHeader.EventCode = model.FILE_OPEN_EVENT
-			obj.CommonHeader.EventName = model.FILE_OPEN_EVENT.String()
-			// Initializes the metadata Opcode for File Open events
-			obj.Metadata = &eventModel.FileOpenMetadata{
-				FileOpenPurposeOp: stateConstants.FILE_OPEN_TO_UNSET, // default value
-			}
-			return obj
-		},
-		model.FILE_RENAME_EVENT: func() any {
-			obj := &model.CommonModel{}
-			obj.CommonHeader.EventCode = model.FILE_RENAME_EVENT
-			obj.CommonHeader.EventName = model.FILE_RENAME_EVENT.String()
-			obj.Metadata = &eventModel.FileRenameMetadata{}
-			return obj
-		},
+// newModel returns the constructor of the pool for the given event code.
+// The metadata structure is created from the event type registry of commonModel.
+func newModel(eventCode model.EventCode) func() any {
+	return func() any {
+		obj := &model.CommonModel{}
+		obj.CommonHeader.EventCode = eventCode
+		obj.CommonHeader.EventName = eventCode.String()
+		obj.CommonHeader.SchemaVersion = model.CURRENT_SCHEMA_VERSION
+		obj.Metadata, _ = model.NewMetadata(eventCode)
+		return obj
 	}
-)
+}
 
 // Pool interface defines the methods for the object pool.
 type Pool interface {
@@ -87,17 +39,21 @@ type Pool interface {
 type eventPool struct {
 	eventPoolMap sync.Map // key: eventModel.EventCode, value: *sync.Pool{eventModel.Event}
 	size         uint32
+	identity     *model.HostIdentity // optional, see WithHostIdentity
 }
 
 // newEventPool initializes a new event pool.
-func NewEventPool() Pool {
+func NewEventPool(opts ...Option) Pool {
 	newPool := new(eventPool)
+	for _, opt := range opts {
+		opt(newPool)
+	}
 
 	newPool.eventPoolMap = sync.Map{}
-	// create a pool for each event type
-	for eventCode, newFunc := range modelMapper {
+	// create a pool for each registered event type
+	for _, eventCode := range model.RegisteredEventCodes() {
 		newPool.eventPoolMap.Store(eventCode, &sync.Pool{
-			New: newFunc,
+			New: newModel(eventCode),
 		})
 	}
 	// create
@@ -115,7 +71,13 @@ func (op *eventPool) Allocate(eventName model.EventCode) (*model.CommonModel, er
 	// check if event pool exists
 	value, isExists = op.eventPoolMap.Load(eventName)
 	if !isExists {
-		return nil, fmt.Errorf(ErrEventNotFound, eventName.String())
+		// event types registered after the pool was created get their pool on first use
+		if _, isExists = model.NewMetadata(eventName); !isExists {
+			return nil, fmt.Errorf(ErrEventNotFound, eventName.String())
+		}
+		value, _ = op.eventPoolMap.LoadOrStore(eventName, &sync.Pool{
+			New: newModel(eventName),
+		})
 	}
 
 	// get event from pool
@@ -133,11 +95,15 @@ func (op *eventPool) Allocate(eventName model.EventCode) (*model.CommonModel, er
 	if !ok {
 		return nil, fmt.Errorf(ErrInvalidTypeAssertionInPool)
 	}
+	if op.identity != nil {
+		event.StampHostIdentity(*op.identity)
+	}
 
 	return event, nil
 }
 
-// Free puts an event model back into the pool.
+// Free resets an event model and puts it back into the pool.
+// Only *model.CommonModel objects, as returned by Allocate, can be freed.
 func (op *eventPool) Free(event eventModel.Event) error {
 	var (
 		value     any
@@ -147,7 +113,11 @@ func (op *eventPool) Free(event eventModel.Event) error {
 	)
 
 	// get event name from event
-	eventName = event.(*model.CommonModel).EventCode
+	cm, ok := event.(*model.CommonModel)
+	if !ok || cm == nil {
+		return fmt.Errorf(ErrInvalidTypeAssertionInPool)
+	}
+	eventName = cm.EventCode
 	// check if event pool exists
 	value, isExists = op.eventPoolMap.Load(eventName)
 	if !isExists {
@@ -155,10 +125,14 @@ func (op *eventPool) Free(event eventModel.Event) error {
 	}
 
 	// put event to pool
-	eventPool, ok := value.(*sync.Pool)
+	eventPool, ok = value.(*sync.Pool)
 	if !ok {
 		return fmt.Errorf(ErrInvalidTypeAssertionInPool)
 	}
-	eventPool.Put(event)
+	// fields of a freed event must not leak into the next allocation
+	if err := cm.Reset(); err != nil {
+		return err
+	}
+	eventPool.Put(cm)
 	return nil
 }
@@ -77,6 +77,90 @@ func TestAllocateInvalidEvent(t *testing.T) {
 	}
 }
 
+// Test Allocation of a registered third-party event
+// Test that event types registered after the pool was created can be allocated
+func TestAllocateRegisteredEvent(t *testing.T) {
+	type customMetadata struct {
+		Device string
+	}
+
+	pool := eventPool.NewEventPool()
+	eventCode := model.USER_EVENT_CODE_MIN + 100
+
+	err := eventModel.Register(eventCode, "PoolTestEvent", func() any { return &customMetadata{} })
+	if err != nil {
+		t.Fatalf("Failed to register event type: %v", err)
+	}
+
+	event, err := pool.Allocate(eventCode)
+	if err != nil {
+		t.Fatalf("Failed to allocate event: %v", err)
+	}
+	if event.EventName != "PoolTestEvent" {
+		t.Fatalf("Allocated event name does not match: got %v, want %v", event.EventName, "PoolTestEvent")
+	}
+	if _, ok := event.Metadata.(*customMetadata); !ok {
+		t.Fatalf("Allocated metadata is not of the registered type: %T", event.Metadata)
+	}
+
+	err = pool.Free(event)
+	if err != nil {
+		t.Fatalf("Failed to free event: %v", err)
+	}
+}
+
+// Test Freeing of events
+// Test that freed events are reset, and that other types are rejected
+func TestFreeResetsEvent(t *testing.T) {
+	pool := eventPool.NewEventPool()
+
+	event, err := pool.Allocate(model.TCP_EVENT)
+	if err != nil {
+		t.Fatalf("Failed to allocate event: %v", err)
+	}
+	metadata := event.Metadata.(*eventModel.TcpMetadata)
+	metadata.PID = 1234
+	event.EventID = "01JXB4T5K3Z8Q2W9E7R6Y5V4N3"
+	event.Seq = 42
+	event.MatchedRuleIDs = model.RuleMatches{{RuleID: "rule1"}}
+	if err = pool.Free(event); err != nil {
+		t.Fatalf("Failed to free event: %v", err)
+	}
+	if event.EventID != "" || event.Seq != 0 || event.MatchedRuleIDs != nil || metadata.PID != 0 {
+		t.Fatalf("Freed event was not reset: %+v, %+v", event.CommonHeader, metadata)
+	}
+	if event.EventCode != model.TCP_EVENT || event.EventName != model.TCP_EVENT.String() || event.Metadata != metadata {
+		t.Fatalf("Freed event lost its type: %+v, %T", event.CommonHeader, event.Metadata)
+	}
+
+	if err = pool.Free(&eventModel.TcpEvent{}); err == nil {
+		t.Fatal("Expected error when freeing another event type, but got none")
+	}
+	if err = pool.Free((*model.CommonModel)(nil)); err == nil {
+		t.Fatal("Expected error when freeing a nil event, but got none")
+	}
+}
+
+// Test Allocation with a host identity
+// Test that every allocated event is stamped with the host identity
+func TestAllocateEventWithHostIdentity(t *testing.T) {
+	identity := model.HostIdentity{HostID: "abcdef", Hostname: "web-01", BootID: "1234", AgentVersion: "1.4.0"}
+	pool := eventPool.NewEventPool(eventPool.WithHostIdentity(identity))
+
+	event, err := pool.Allocate(model.TCP_EVENT)
+	if err != nil {
+		t.Fatalf("Failed to allocate event: %v", err)
+	}
+	if event.HostID != identity.HostID || event.Hostname != identity.Hostname || event.AgentVersion != identity.AgentVersion {
+		t.Fatalf("Allocated event is not stamped: %+v", event.CommonHeader)
+	}
+
+	err = pool.Free(event)
+	if err != nil {
+		t.Fatalf("Failed to free event: %v", err)
+	}
+}
+
 // Test Freeing of a valid event
 // Test that a valid event can be freed back to the pool
 func TestFreeEvent(t *testing.T) {
@@ -0,0 +1,17 @@
+// pool/options.go
+
+package eventPool
+
+import (
+	model "github.com/enki-polvo/polvo-logger/model"
+)
+
+// Option configures the event pool created by NewEventPool.
+type Option func(*eventPool)
+
+// WithHostIdentity makes Allocate stamp the host identity on the header of every event.
+func WithHostIdentity(identity model.HostIdentity) Option {
+	return func(op *eventPool) {
+		op.identity = &identity
+	}
+}
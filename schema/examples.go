@@ -0,0 +1,164 @@
+// schema/examples.go
+package jsonSchema
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+const (
+	exampleMarker = "example:"
+)
+
+const (
+	ErrNoModelExamples = "no example found in %s, the source directory must be the root of the polvo-logger module"
+)
+
+// MODEL_SOURCE_DIRS are the directories of the common and event model sources, relative to the module root.
+var MODEL_SOURCE_DIRS = []string{"model", "model/event"}
+
+// Examples maps a struct type name and a field name to the example values of the field,
+// e.g. Examples["TcpMetadata"]["Dport"] = []any{80}.
+type Examples map[string]map[string][]any
+
+// CollectModelExamples reads the examples of the common and event models in the sources of
+// the polvo-logger module whose root is fsys, e.g. os.DirFS of a checkout.
+// It fails if a model directory holds no example, e.g. because fsys is not the module root,
+// so that schemas are not silently generated without examples.
+func CollectModelExamples(fsys fs.FS) (Examples, error) {
+	examples := Examples{}
+	for _, dir := range MODEL_SOURCE_DIRS {
+		sources, err := fs.Sub(fsys, dir)
+		if err != nil {
+			return nil, err
+		}
+		dirExamples, err := CollectExamples(sources)
+		if err != nil {
+			return nil, err
+		}
+		if len(dirExamples) == 0 {
+			return nil, fmt.Errorf(ErrNoModelExamples, dir)
+		}
+		examples.Merge(dirExamples)
+	}
+	return examples, nil
+}
+
+// CollectExamples reads the `// example:` comments of the struct fields in the Go sources of fsys.
+// Test files are skipped.
+// Only the leading literals of a comment are kept, so `// example: 100 (Number of bytes)` yields 100.
+func CollectExamples(fsys fs.FS) (Examples, error) {
+	examples := Examples{}
+
+	files, err := fs.Glob(fsys, "*.go")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		src, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		if err = examples.parse(name, src); err != nil {
+			return nil, err
+		}
+	}
+	return examples, nil
+}
+
+// Merge adds the examples of other, overriding existing entries.
+func (e Examples) Merge(other Examples) {
+	for typeName, fields := range other {
+		if e[typeName] == nil {
+			e[typeName] = map[string][]any{}
+		}
+		for fieldName, values := range fields {
+			e[typeName][fieldName] = values
+		}
+	}
+}
+
+// parse collects the examples of a single Go source file.
+func (e Examples) parse(filename string, src []byte) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		typeSpec, ok := node.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return false
+		}
+		for _, field := range structType.Fields.List {
+			if field.Comment == nil || len(field.Names) == 0 {
+				continue
+			}
+			values := parseExample(field.Comment.Text())
+			if len(values) == 0 {
+				continue
+			}
+			if e[typeSpec.Name.Name] == nil {
+				e[typeSpec.Name.Name] = map[string][]any{}
+			}
+			for _, fieldName := range field.Names {
+				e[typeSpec.Name.Name][fieldName.Name] = values
+			}
+		}
+		return false
+	})
+	return nil
+}
+
+// parseExample returns the leading Go literals following the example marker of a comment.
+func parseExample(comment string) (values []any) {
+	_, text, isExists := strings.Cut(comment, exampleMarker)
+	if !isExists {
+		return nil
+	}
+
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	src := []byte(strings.TrimSpace(text))
+	s.Init(fset.AddFile("", fset.Base(), len(src)), src, nil, 0)
+
+	for {
+		_, tok, lit := s.Scan()
+		switch tok {
+		case token.INT:
+			number, err := strconv.ParseInt(lit, 0, 64)
+			if err != nil {
+				return values
+			}
+			values = append(values, number)
+		case token.FLOAT:
+			number, err := strconv.ParseFloat(lit, 64)
+			if err != nil {
+				return values
+			}
+			values = append(values, number)
+		case token.STRING:
+			str, err := strconv.Unquote(lit)
+			if err != nil {
+				return values
+			}
+			values = append(values, str)
+		default:
+			return values
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// schema/schema.go
+package jsonSchema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	// the built-in event types are registered by the eventModel package
+	_ "github.com/enki-polvo/polvo-logger/model/event"
+)
+
+// DRAFT is the JSON Schema dialect of the generated documents.
+const DRAFT = "https://json-schema.org/draft/2020-12/schema"
+
+// maxEnumProbe bounds the values probed when listing the names of an enum.
+const maxEnumProbe = 256
+
+const (
+	ErrUnknownEventCode = "unknown event code: %d"
+)
+
+// Schema is a JSON Schema document or sub-schema.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Minimum     *int64             `json:"minimum,omitempty"`
+	Const       any                `json:"const,omitempty"`
+	Enum        []any              `json:"enum,omitempty"`
+	OneOf       []*Schema          `json:"oneOf,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Additional  *Schema            `json:"additionalProperties,omitempty"`
+	Examples    []any              `json:"examples,omitempty"`
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// Generate returns the JSON Schema document of the events with the given code, without examples.
+func Generate(code commonModel.EventCode) (*Schema, error) {
+	return GenerateWithExamples(code, nil)
+}
+
+// GenerateAll returns the JSON Schema documents of every registered event type, without examples.
+func GenerateAll() (map[commonModel.EventCode]*Schema, error) {
+	return GenerateAllWithExamples(nil)
+}
+
+// GenerateAllWithExamples returns the JSON Schema documents of every registered event type.
+func GenerateAllWithExamples(examples Examples) (map[commonModel.EventCode]*Schema, error) {
+	schemas := map[commonModel.EventCode]*Schema{}
+	for _, code := range commonModel.RegisteredEventCodes() {
+		schema, err := GenerateWithExamples(code, examples)
+		if err != nil {
+			return nil, err
+		}
+		schemas[code] = schema
+	}
+	return schemas, nil
+}
+
+// GenerateWithExamples returns the JSON Schema document of the events with the given code.
+// The document describes a CommonModel whose Metadata is the registered metadata structure of the code.
+func GenerateWithExamples(code commonModel.EventCode, examples Examples) (*Schema, error) {
+	metadata, isExists := commonModel.NewMetadata(code)
+	if !isExists {
+		return nil, fmt.Errorf(ErrUnknownEventCode, code)
+	}
+
+	g := &generator{examples: examples}
+	schema := g.structSchema(reflect.TypeOf(commonModel.CommonHeader{}))
+	schema.Schema = DRAFT
+	schema.Title = code.String()
+	schema.Description = fmt.Sprintf("%s event (EventCode %d, SchemaVersion %d)", code.String(), code, commonModel.CURRENT_SCHEMA_VERSION)
+
+	// the header of a single event type has a fixed code and name
+	schema.Properties["EventCode"] = &Schema{
+		OneOf: []*Schema{
+			{Type: "integer", Const: int64(code)},
+			{Type: "string", Const: code.String()},
+		},
+	}
+	schema.Properties["EventName"] = &Schema{Type: "string", Const: code.String()}
+
+	metadataSchema := g.schemaOf(reflect.TypeOf(metadata))
+	metadataSchema.Title = reflect.TypeOf(metadata).Elem().Name()
+	schema.Properties["Metadata"] = metadataSchema
+	schema.Required = append(schema.Required, "Metadata")
+	return schema, nil
+}
+
+// generator builds schemas from Go types.
+type generator struct {
+	examples Examples
+}
+
+// schemaOf returns the schema of a Go type.
+func (g *generator) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	if isEnum(t) {
+		return enumSchema(t)
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		minimum := int64(0)
+		return &Schema{Type: "integer", Minimum: &minimum}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", Additional: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		// interfaces and other kinds accept any value
+		return &Schema{}
+	}
+}
+
+// structSchema returns the object schema of a struct.
+// Embedded structs are flattened, the way encoding/json does.
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.addFields(schema, t)
+	return schema
+}
+
+// addFields adds the JSON fields of the struct t to schema.
+func (g *generator) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitempty, skip := jsonField(field)
+		if skip {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && name == "" {
+			g.addFields(schema, field.Type)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema := g.schemaOf(field.Type)
+		fieldSchema.Examples = g.examples[t.Name()][field.Name]
+		schema.Properties[name] = fieldSchema
+		if !omitempty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// jsonField returns the name and the omitempty option of a struct field's json tag.
+// skip reports whether encoding/json ignores the field.
+func jsonField(field reflect.StructField) (name string, omitempty bool, skip bool) {
+	if !field.IsExported() && !field.Anonymous {
+		return "", false, true
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	return name, strings.Contains(options, "omitempty"), false
+}
+
+// isEnum reports whether t is an integer enum with names, like state.TcpOp.
+func isEnum(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return t.PkgPath() != "" && t.Implements(stringerType)
+	default:
+		return false
+	}
+}
+
+// enumSchema returns the schema of an enum, accepting both its numbers and its names.
+func enumSchema(t reflect.Type) *Schema {
+	numbers := &Schema{Type: "integer"}
+	names := &Schema{Type: "string"}
+
+	value := reflect.New(t).Elem()
+	for i := int64(0); i < maxEnumProbe; i++ {
+		value.SetInt(i)
+		name := value.Interface().(fmt.Stringer).String()
+		if name == "" {
+			continue
+		}
+		numbers.Enum = append(numbers.Enum, i)
+		names.Enum = append(names.Enum, name)
+	}
+	return &Schema{Title: t.Name(), OneOf: []*Schema{numbers, names}}
+}
@@ -0,0 +1,74 @@
+package jsonSchema_test
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"testing"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	jsonSchema "github.com/enki-polvo/polvo-logger/schema"
+)
+
+// Test generation of a file open event schema
+// Test that header, metadata, enum names and examples are part of the schema
+func TestGenerateFileOpenSchema(t *testing.T) {
+	// tests run in the package directory, the module root is its parent
+	examples, err := jsonSchema.CollectModelExamples(os.DirFS(".."))
+	if err != nil {
+		t.Fatalf("Failed to collect examples: %v", err)
+	}
+	// a directory that is not the module root has no examples
+	if _, err = jsonSchema.CollectModelExamples(os.DirFS(t.TempDir())); err == nil {
+		t.Fatal("Expected an error when collecting examples outside of the module, but got none")
+	}
+
+	schema, err := jsonSchema.GenerateWithExamples(commonModel.FILE_OPEN_EVENT, examples)
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	for _, name := range []string{"EventCode", "EventName", "Source", "Timestamp", "SchemaVersion", "Metadata"} {
+		if _, isExists := schema.Properties[name]; !isExists {
+			t.Fatalf("Schema is missing header property %v", name)
+		}
+	}
+	if schema.Properties["Timestamp"].Format != "date-time" {
+		t.Fatalf("Timestamp format does not match: got %v", schema.Properties["Timestamp"].Format)
+	}
+
+	metadata := schema.Properties["Metadata"]
+	purpose, isExists := metadata.Properties["FileOperationType"]
+	if !isExists || len(purpose.OneOf) != 2 {
+		t.Fatalf("FileOperationType is not an enum schema: %+v", purpose)
+	}
+	if !slices.Contains(purpose.OneOf[1].Enum, any("FILE_OPEN_TO_WRITE")) {
+		t.Fatalf("FileOperationType enum is missing names: %+v", purpose.OneOf[1].Enum)
+	}
+
+	// 0444 is an octal literal in the source
+	mode := metadata.Properties["Mode"]
+	if len(mode.Examples) != 1 || mode.Examples[0] != int64(0444) {
+		t.Fatalf("Mode examples do not match: %v", mode.Examples)
+	}
+
+	if _, err = json.Marshal(schema); err != nil {
+		t.Fatalf("Failed to marshal schema: %v", err)
+	}
+}
+
+// Test generation of every registered event type
+// Test that unknown codes fail and every built-in code produces a schema
+func TestGenerateAll(t *testing.T) {
+	schemas, err := jsonSchema.GenerateAll()
+	if err != nil {
+		t.Fatalf("Failed to generate schemas: %v", err)
+	}
+	if len(schemas) < 7 {
+		t.Fatalf("Expected a schema for every built-in event type, got %d", len(schemas))
+	}
+
+	if _, err = jsonSchema.Generate(commonModel.EventCode(999)); err == nil {
+		t.Fatal("Expected error when generating an unknown event code, but got none")
+	}
+}
@@ -0,0 +1,242 @@
+// tracker/file.go
+package entityTracker
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	entityModel "github.com/enki-polvo/polvo-logger/model/entity"
+	eventModel "github.com/enki-polvo/polvo-logger/model/event"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+// fileKey identifies a file entity.
+type fileKey struct {
+	inode int64
+	path  string
+}
+
+// trackedFile is a file entity with the bookkeeping of the aggregator.
+type trackedFile struct {
+	entity   *entityModel.FileEntityModel
+	lastSeen time.Time // the timestamp of the last event of the file
+}
+
+// FileOption configures a FileAggregator.
+type FileOption func(*FileAggregator)
+
+// WithFileIdleTTL sets how long Evict keeps a file without event. The default is DEFAULT_IDLE_TTL.
+func WithFileIdleTTL(ttl time.Duration) FileOption {
+	return func(a *FileAggregator) {
+		a.idleTTL = ttl
+	}
+}
+
+// FileAggregator aggregates a stream of FileOpen and FileRename events into file entities.
+// Files never terminate, so Evict must be called periodically to forget the idle ones.
+// It is safe for concurrent use.
+type FileAggregator struct {
+	mu      sync.RWMutex
+	files   map[fileKey]*trackedFile
+	paths   map[string]fileKey // the latest file opened or renamed to a path
+	idleTTL time.Duration
+}
+
+// NewFileAggregator returns an empty FileAggregator.
+func NewFileAggregator(opts ...FileOption) *FileAggregator {
+	a := &FileAggregator{
+		files:   map[fileKey]*trackedFile{},
+		paths:   map[string]fileKey{},
+		idleTTL: DEFAULT_IDLE_TTL,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Track updates the file entities with an event.
+// It accepts *eventModel.FileOpenEvent and *eventModel.FileRenameEvent,
+// as well as *commonModel.CommonModel holding their metadata.
+// It returns a copy of the updated entity, or false if the event is not a file event
+// or renames a path that is not tracked.
+func (a *FileAggregator) Track(event eventModel.Event) (*entityModel.FileEntityModel, bool) {
+	switch e := event.(type) {
+	case *eventModel.FileOpenEvent:
+		return a.open(e.Timestamp, &e.Metadata), true
+	case *eventModel.FileRenameEvent:
+		return a.rename(e.Timestamp, &e.Metadata)
+	case *commonModel.CommonModel:
+		switch metadata := e.Metadata.(type) {
+		case *eventModel.FileOpenMetadata:
+			return a.open(e.Timestamp, metadata), true
+		case *eventModel.FileRenameMetadata:
+			return a.rename(e.Timestamp, metadata)
+		}
+	}
+	return nil, false
+}
+
+// open counts a file open by its purpose and records the opener and the owner of the file.
+func (a *FileAggregator) open(timestamp time.Time, m *eventModel.FileOpenMetadata) *entityModel.FileEntityModel {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	file := a.get(fileKey{inode: m.Inode, path: m.Path}, timestamp).entity
+	switch m.FileOpenPurposeOp {
+	case state.FILE_OPEN_TO_READ:
+		file.NumReadOps++
+	case state.FILE_OPEN_TO_WRITE:
+		file.NumWriteOps++
+	}
+	file.LastOpenerUID = m.FileOpenerUID
+	file.LastOpenerPID = m.PID
+	file.OwnerUID = m.FileOwnerUID
+	file.OwnerGID = m.FileOwnerGID
+	file.OwnerUsername = m.FileOwnerUsername
+	return cloneFile(file)
+}
+
+// rename moves the latest file at the old path to the new path and records the rename.
+// Rename events carry no inode, so a path that is not tracked is not renamed.
+// If the new path already has an entity of the same inode, e.g. another hard link of the file,
+// the renamed entity is merged into it.
+func (a *FileAggregator) rename(timestamp time.Time, m *eventModel.FileRenameMetadata) (*entityModel.FileEntityModel, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key, isExists := a.paths[m.OldPath]
+	if !isExists {
+		return nil, false
+	}
+	file := a.get(key, timestamp)
+	delete(a.files, key)
+	delete(a.paths, m.OldPath)
+
+	rename := entityModel.FileRename{
+		OldPath:   m.OldPath,
+		NewPath:   m.NewPath,
+		PID:       m.PID,
+		UID:       m.UID,
+		Timestamp: timestamp,
+	}
+	key.path = m.NewPath
+	if target, isExists := a.files[key]; isExists {
+		mergeFile(target.entity, file.entity)
+		target.entity.State = state.MODIFIED
+		target.entity.RenameHistory = append(target.entity.RenameHistory, rename)
+		target.lastSeen = timestamp
+		a.paths[m.NewPath] = key
+		return cloneFile(target.entity), true
+	}
+
+	a.files[key] = file
+	a.paths[m.NewPath] = key
+	file.entity.Path = m.NewPath
+	file.entity.RenameHistory = append(file.entity.RenameHistory, rename)
+	return cloneFile(file.entity), true
+}
+
+// mergeFile adds the counters and the rename history of a file entity into another entity of the same inode.
+// The opener and the owner of into are kept.
+func mergeFile(into, from *entityModel.FileEntityModel) {
+	into.NumReadOps += from.NumReadOps
+	into.NumWriteOps += from.NumWriteOps
+	into.RenameHistory = append(into.RenameHistory, from.RenameHistory...)
+	slices.SortStableFunc(into.RenameHistory, func(a, b entityModel.FileRename) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+}
+
+// get returns the entity of the key, creating it if it does not exist, and records the
+// timestamp as its last event. An existing entity becomes MODIFIED. The caller must hold the lock.
+func (a *FileAggregator) get(key fileKey, timestamp time.Time) *trackedFile {
+	file, isExists := a.files[key]
+	if isExists {
+		file.entity.State = state.MODIFIED
+		file.lastSeen = timestamp
+		return file
+	}
+
+	file = &trackedFile{
+		entity: &entityModel.FileEntityModel{
+			CommonEntityModel: entityModel.CommonEntityModel{
+				EntityType: entityModel.FILE_ENTITY,
+				State:      state.CREATED,
+			},
+			Inode: key.inode,
+			Path:  key.path,
+		},
+		lastSeen: timestamp,
+	}
+	a.files[key] = file
+	a.paths[key.path] = key
+	return file
+}
+
+// Evict removes the files without event within the idle TTL, and returns them
+// sorted by EntityID. A zero idle TTL keeps every file.
+func (a *FileAggregator) Evict(now time.Time) []*entityModel.FileEntityModel {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.idleTTL <= 0 {
+		return nil
+	}
+
+	var evicted []*entityModel.FileEntityModel
+	for key, file := range a.files {
+		if now.Sub(file.lastSeen) < a.idleTTL {
+			continue
+		}
+		delete(a.files, key)
+		if a.paths[key.path] == key {
+			delete(a.paths, key.path)
+		}
+		evicted = append(evicted, file.entity)
+	}
+	slices.SortFunc(evicted, func(a, b *entityModel.FileEntityModel) int {
+		return strings.Compare(a.EntityID(), b.EntityID())
+	})
+	return evicted
+}
+
+// Lookup returns a copy of the file entity with the given inode and path.
+func (a *FileAggregator) Lookup(inode int64, path string) (*entityModel.FileEntityModel, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	file, isExists := a.files[fileKey{inode: inode, path: path}]
+	if !isExists {
+		return nil, false
+	}
+	return cloneFile(file.entity), true
+}
+
+// LookupPath returns a copy of the latest file entity opened or renamed to the path.
+func (a *FileAggregator) LookupPath(path string) (*entityModel.FileEntityModel, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	key, isExists := a.paths[path]
+	if !isExists {
+		return nil, false
+	}
+	return cloneFile(a.files[key].entity), true
+}
+
+// Len returns the number of file entities.
+func (a *FileAggregator) Len() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return len(a.files)
+}
+
+// cloneFile returns a copy of the file entity that does not share its rename history.
+func cloneFile(file *entityModel.FileEntityModel) *entityModel.FileEntityModel {
+	return file.Clone().(*entityModel.FileEntityModel)
+}
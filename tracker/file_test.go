@@ -0,0 +1,125 @@
+package entityTracker_test
+
+import (
+	"testing"
+	"time"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	eventModel "github.com/enki-polvo/polvo-logger/model/event"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+	entityTracker "github.com/enki-polvo/polvo-logger/tracker"
+)
+
+func fileOpen(pid, inode int64, path string, op state.FileOpenPurposeOp) *eventModel.FileOpenEvent {
+	return &eventModel.FileOpenEvent{
+		CommonHeader: commonModel.CommonHeader{EventCode: commonModel.FILE_OPEN_EVENT, Timestamp: baseTime},
+		Metadata: eventModel.FileOpenMetadata{
+			PID:               pid,
+			FileOpenerUID:     1000,
+			FileOwnerUsername: "root",
+			FileOpenPurposeOp: op,
+			Inode:             inode,
+			Path:              path,
+		},
+	}
+}
+
+// Test the counters of a file entity
+func TestFileAggregatorCounters(t *testing.T) {
+	aggregator := entityTracker.NewFileAggregator()
+
+	file, ok := aggregator.Track(fileOpen(100, 42, "/etc/passwd", state.FILE_OPEN_TO_READ))
+	if !ok || file.State != state.CREATED || file.NumReadOps != 1 {
+		t.Fatalf("Failed to track file open: %+v", file)
+	}
+	aggregator.Track(fileOpen(101, 42, "/etc/passwd", state.FILE_OPEN_TO_READ))
+	aggregator.Track(fileOpen(102, 42, "/etc/passwd", state.FILE_OPEN_TO_OTHER))
+	file, _ = aggregator.Track(fileOpen(103, 42, "/etc/passwd", state.FILE_OPEN_TO_WRITE))
+
+	if file.State != state.MODIFIED || file.NumReadOps != 2 || file.NumWriteOps != 1 {
+		t.Fatalf("File counters do not match: %+v", file)
+	}
+	if file.LastOpenerPID != 103 || file.LastOpenerUID != 1000 || file.OwnerUsername != "root" {
+		t.Fatalf("File opener or owner does not match: %+v", file)
+	}
+
+	// the same path with another inode is another file
+	aggregator.Track(fileOpen(104, 43, "/etc/passwd", state.FILE_OPEN_TO_READ))
+	if aggregator.Len() != 2 {
+		t.Fatalf("Unexpected number of file entities: got %d, want 2", aggregator.Len())
+	}
+}
+
+// Test the rename history of a file entity
+func TestFileAggregatorRename(t *testing.T) {
+	aggregator := entityTracker.NewFileAggregator()
+	aggregator.Track(fileOpen(100, 42, "/var/log/syslog", state.FILE_OPEN_TO_WRITE))
+
+	for i, path := range []string{"/var/log/syslog.1", "/var/log/syslog.2"} {
+		oldPath := "/var/log/syslog"
+		if i > 0 {
+			oldPath = "/var/log/syslog.1"
+		}
+		aggregator.Track(&eventModel.FileRenameEvent{
+			CommonHeader: commonModel.CommonHeader{Timestamp: baseTime.Add(time.Duration(i) * time.Second)},
+			Metadata:     eventModel.FileRenameMetadata{PID: 200, OldPath: oldPath, NewPath: path},
+		})
+	}
+
+	if _, ok := aggregator.LookupPath("/var/log/syslog"); ok {
+		t.Fatal("Renamed file is still found by its old path")
+	}
+	file, ok := aggregator.Lookup(42, "/var/log/syslog.2")
+	if !ok {
+		t.Fatal("Failed to look up the renamed file")
+	}
+	if file.NumWriteOps != 1 || len(file.RenameHistory) != 2 || file.RenameHistory[1].OldPath != "/var/log/syslog.1" {
+		t.Fatalf("Renamed file does not match: %+v", file)
+	}
+}
+
+func fileRename(oldPath, newPath string, offset time.Duration) *eventModel.FileRenameEvent {
+	return &eventModel.FileRenameEvent{
+		CommonHeader: commonModel.CommonHeader{EventCode: commonModel.FILE_RENAME_EVENT, Timestamp: baseTime.Add(offset)},
+		Metadata:     eventModel.FileRenameMetadata{PID: 200, OldPath: oldPath, NewPath: newPath},
+	}
+}
+
+// Test renames of paths that are not tracked and renames onto a tracked link of the same file
+func TestFileAggregatorRenameConflicts(t *testing.T) {
+	aggregator := entityTracker.NewFileAggregator()
+
+	if file, ok := aggregator.Track(fileRename("/tmp/untracked", "/tmp/renamed", 0)); ok || aggregator.Len() != 0 {
+		t.Fatalf("Renamed a file that is not tracked: %+v", file)
+	}
+
+	// two hard links of inode 42
+	aggregator.Track(fileOpen(100, 42, "/srv/a", state.FILE_OPEN_TO_READ))
+	aggregator.Track(fileOpen(100, 42, "/srv/b", state.FILE_OPEN_TO_WRITE))
+	file, ok := aggregator.Track(fileRename("/srv/a", "/srv/b", time.Second))
+	if !ok || aggregator.Len() != 1 {
+		t.Fatalf("Failed to merge the renamed link: %d entities", aggregator.Len())
+	}
+	if file.Inode != 42 || file.Path != "/srv/b" || file.NumReadOps != 1 || file.NumWriteOps != 1 || len(file.RenameHistory) != 1 {
+		t.Fatalf("Merged file does not match: %+v", file)
+	}
+}
+
+// Test that files without event within the idle TTL are evicted
+func TestFileAggregatorEvict(t *testing.T) {
+	aggregator := entityTracker.NewFileAggregator(entityTracker.WithFileIdleTTL(10 * time.Minute))
+	aggregator.Track(fileOpen(100, 42, "/etc/passwd", state.FILE_OPEN_TO_READ))
+	aggregator.Track(fileOpen(100, 43, "/etc/shadow", state.FILE_OPEN_TO_READ))
+	aggregator.Track(fileRename("/etc/shadow", "/etc/shadow-", 5*time.Minute))
+
+	evicted := aggregator.Evict(baseTime.Add(10 * time.Minute))
+	if len(evicted) != 1 || evicted[0].Path != "/etc/passwd" || aggregator.Len() != 1 {
+		t.Fatalf("Failed to evict the idle file: %v", evicted)
+	}
+	if _, ok := aggregator.LookupPath("/etc/passwd"); ok {
+		t.Fatal("Evicted file is still found by its path")
+	}
+	if evicted = aggregator.Evict(baseTime.Add(15 * time.Minute)); len(evicted) != 1 || aggregator.Len() != 0 {
+		t.Fatalf("Failed to evict the renamed file: %v", evicted)
+	}
+}
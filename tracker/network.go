@@ -0,0 +1,289 @@
+// tracker/network.go
+package entityTracker
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	entityModel "github.com/enki-polvo/polvo-logger/model/entity"
+	eventModel "github.com/enki-polvo/polvo-logger/model/event"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+// DEFAULT_LONG_LIVED_AFTER is the default age after which an open connection is long-lived.
+const DEFAULT_LONG_LIVED_AFTER = time.Hour
+
+// ConnectionKey identifies a TCP connection by its 5-tuple and the PID of its process.
+type ConnectionKey struct {
+	PID      int64
+	Protocol int64
+	Saddr    string
+	Sport    int64
+	Daddr    string
+	Dport    int64
+}
+
+// Traffic is the traffic of a connection since the last report.
+type Traffic struct {
+	RecvOps   int64
+	SentOps   int64
+	RecvBytes int64
+	SentBytes int64
+}
+
+// TrafficMetadata is implemented by metadata reporting the traffic of a connection,
+// e.g. the metadata of a registered third-party event type.
+type TrafficMetadata interface {
+	Connection() ConnectionKey
+	Traffic() Traffic
+}
+
+// connection is an open connection with the bookkeeping of the tracker.
+type connection struct {
+	entity *entityModel.NetworkEntityModel
+	// ownerTracked reports whether the owning process was tracked when the connection was opened
+	ownerTracked bool
+}
+
+// ConnectionOption configures a ConnectionTracker.
+type ConnectionOption func(*ConnectionTracker)
+
+// WithProcessTracker resolves the owning process of connections with the given tracker.
+// Connections whose owning process has exited are reported as leaked by Sweep.
+func WithProcessTracker(processes *ProcessTracker) ConnectionOption {
+	return func(t *ConnectionTracker) {
+		t.processes = processes
+	}
+}
+
+// WithLongLivedAfter sets the age after which an open connection is reported as long-lived by Sweep.
+func WithLongLivedAfter(age time.Duration) ConnectionOption {
+	return func(t *ConnectionTracker) {
+		t.longLivedAfter = age
+	}
+}
+
+// ConnectionTracker builds network entities from TcpEvents.
+// TCP_CONNECT and TCP_ACCEPT open a connection, which TCP_DISCONNECT closes.
+// It is safe for concurrent use.
+type ConnectionTracker struct {
+	mu             sync.Mutex
+	open           map[ConnectionKey]*connection
+	processes      *ProcessTracker
+	longLivedAfter time.Duration
+}
+
+// NewConnectionTracker returns an empty ConnectionTracker.
+func NewConnectionTracker(opts ...ConnectionOption) *ConnectionTracker {
+	t := &ConnectionTracker{
+		open:           map[ConnectionKey]*connection{},
+		longLivedAfter: DEFAULT_LONG_LIVED_AFTER,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Track updates the network entities with an event.
+// It accepts *eventModel.TcpEvent, as well as *commonModel.CommonModel holding
+// TcpMetadata or metadata implementing TrafficMetadata.
+// It returns a copy of the updated entity, or false if the event is not a network event
+// or does not belong to an open connection.
+func (t *ConnectionTracker) Track(event eventModel.Event) (*entityModel.NetworkEntityModel, bool) {
+	switch e := event.(type) {
+	case *eventModel.TcpEvent:
+		return t.tcp(e.Timestamp, &e.Metadata)
+	case *commonModel.CommonModel:
+		switch metadata := e.Metadata.(type) {
+		case *eventModel.TcpMetadata:
+			return t.tcp(e.Timestamp, metadata)
+		case TrafficMetadata:
+			return t.traffic(metadata)
+		}
+	}
+	return nil, false
+}
+
+// tcp opens or closes a connection.
+func (t *ConnectionTracker) tcp(timestamp time.Time, m *eventModel.TcpMetadata) (*entityModel.NetworkEntityModel, bool) {
+	key := ConnectionKey{PID: m.PID, Protocol: m.Protocol, Saddr: m.Saddr, Sport: m.Sport, Daddr: m.Daddr, Dport: m.Dport}
+	switch m.Op {
+	case state.TCP_CONNECT:
+		return t.connect(timestamp, key, entityModel.DIRECTION_OUTBOUND), true
+	case state.TCP_ACCEPT:
+		return t.connect(timestamp, key, entityModel.DIRECTION_INBOUND), true
+	case state.TCP_DISCONNECT:
+		return t.disconnect(timestamp, key)
+	default:
+		return nil, false
+	}
+}
+
+// connect opens a connection. It replaces an open connection with the same key.
+func (t *ConnectionTracker) connect(timestamp time.Time, key ConnectionKey, direction entityModel.Direction) *entityModel.NetworkEntityModel {
+	entity := &entityModel.NetworkEntityModel{
+		CommonEntityModel: entityModel.CommonEntityModel{
+			EntityType: entityModel.NETWORK_ENTITY,
+			State:      state.CREATED,
+		},
+		PID:       key.PID,
+		Protocol:  key.Protocol,
+		Saddr:     key.Saddr,
+		Sport:     key.Sport,
+		Daddr:     key.Daddr,
+		Dport:     key.Dport,
+		Direction: direction,
+		StartTime: timestamp,
+	}
+
+	conn := &connection{entity: entity}
+	if t.processes != nil {
+		if process, isExists := t.processes.Lookup(key.PID); isExists {
+			entity.OwnerImage = process.Image
+			conn.ownerTracked = true
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.open[key] = conn
+	return cloneConnection(entity)
+}
+
+// Seed tracks connections that were opened before the tracker, e.g. the Connections of an Inventory.
+// Connections that are already open are kept.
+func (t *ConnectionTracker) Seed(connections ...*entityModel.NetworkEntityModel) {
+	for _, entity := range connections {
+		key := ConnectionKey{PID: entity.PID, Protocol: entity.Protocol, Saddr: entity.Saddr, Sport: entity.Sport, Daddr: entity.Daddr, Dport: entity.Dport}
+		conn := &connection{entity: cloneConnection(entity)}
+		if t.processes != nil {
+			_, conn.ownerTracked = t.processes.Lookup(entity.PID)
+		}
+
+		t.mu.Lock()
+		if _, isExists := t.open[key]; !isExists {
+			t.open[key] = conn
+		}
+		t.mu.Unlock()
+	}
+}
+
+// disconnect closes a connection.
+// A connection closed by another process than the one that opened it (e.g. an inherited socket)
+// is matched by its 5-tuple alone.
+func (t *ConnectionTracker) disconnect(timestamp time.Time, key ConnectionKey) (*entityModel.NetworkEntityModel, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	conn, isExists := t.open[key]
+	if !isExists {
+		if key, conn, isExists = t.findTuple(key); !isExists {
+			return nil, false
+		}
+	}
+	delete(t.open, key)
+
+	entity := conn.entity
+	entity.State = state.MODIFIED
+	entity.EndTime = timestamp
+	entity.Duration = timestamp.Sub(entity.StartTime)
+	return entity, true
+}
+
+// findTuple returns the open connection with the 5-tuple of key, opened by any process.
+// The caller must hold the lock.
+func (t *ConnectionTracker) findTuple(key ConnectionKey) (ConnectionKey, *connection, bool) {
+	for openKey, conn := range t.open {
+		tuple := openKey
+		tuple.PID = key.PID
+		if tuple == key {
+			return openKey, conn, true
+		}
+	}
+	return key, nil, false
+}
+
+// traffic adds the reported traffic to the counters of an open connection.
+func (t *ConnectionTracker) traffic(m TrafficMetadata) (*entityModel.NetworkEntityModel, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	conn, isExists := t.open[m.Connection()]
+	if !isExists {
+		return nil, false
+	}
+
+	traffic := m.Traffic()
+	entity := conn.entity
+	entity.State = state.MODIFIED
+	entity.NumRecvOps += traffic.RecvOps
+	entity.NumSentOps += traffic.SentOps
+	entity.NumRecvBytes += traffic.RecvBytes
+	entity.NumSentBytes += traffic.SentBytes
+	return cloneConnection(entity), true
+}
+
+// Lookup returns a copy of the open connection with the given key.
+func (t *ConnectionTracker) Lookup(key ConnectionKey) (*entityModel.NetworkEntityModel, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	conn, isExists := t.open[key]
+	if !isExists {
+		return nil, false
+	}
+	return cloneConnection(conn.entity), true
+}
+
+// Sweep flags the open connections that have no disconnect yet and returns copies of them,
+// sorted by start time:
+// a connection open for longer than the long-lived age becomes LongLived, and
+// a connection whose owning process is no longer tracked by the process tracker becomes Leaked.
+// The connections stay open; their Duration is their age at now.
+func (t *ConnectionTracker) Sweep(now time.Time) []*entityModel.NetworkEntityModel {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var flagged []*entityModel.NetworkEntityModel
+	for _, conn := range t.open {
+		entity := conn.entity
+		longLived := now.Sub(entity.StartTime) >= t.longLivedAfter
+		leaked := false
+		if conn.ownerTracked {
+			_, alive := t.processes.Lookup(entity.PID)
+			leaked = !alive
+		}
+		if !longLived && !leaked {
+			continue
+		}
+
+		if longLived != entity.LongLived || leaked != entity.Leaked {
+			entity.State = state.MODIFIED
+		}
+		entity.LongLived = longLived
+		entity.Leaked = leaked
+		entity.Duration = now.Sub(entity.StartTime)
+		flagged = append(flagged, cloneConnection(entity))
+	}
+	slices.SortFunc(flagged, func(a, b *entityModel.NetworkEntityModel) int {
+		return a.StartTime.Compare(b.StartTime)
+	})
+	return flagged
+}
+
+// cloneConnection returns a copy of the network entity that does not share its rule matches.
+func cloneConnection(entity *entityModel.NetworkEntityModel) *entityModel.NetworkEntityModel {
+	return entity.Clone().(*entityModel.NetworkEntityModel)
+}
+
+// Len returns the number of open connections.
+func (t *ConnectionTracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.open)
+}
@@ -0,0 +1,122 @@
+package entityTracker_test
+
+import (
+	"testing"
+	"time"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	entityModel "github.com/enki-polvo/polvo-logger/model/entity"
+	eventModel "github.com/enki-polvo/polvo-logger/model/event"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+	entityTracker "github.com/enki-polvo/polvo-logger/tracker"
+)
+
+func tcpEvent(pid, sport int64, op state.TcpOp, at time.Duration) *eventModel.TcpEvent {
+	return &eventModel.TcpEvent{
+		CommonHeader: commonModel.CommonHeader{EventCode: commonModel.TCP_EVENT, Timestamp: baseTime.Add(at)},
+		Metadata: eventModel.TcpMetadata{
+			PID:      pid,
+			Saddr:    "10.0.0.1",
+			Sport:    sport,
+			Daddr:    "10.0.0.2",
+			Dport:    443,
+			Protocol: 4,
+			Op:       op,
+		},
+	}
+}
+
+// trafficMetadata reports the traffic of a connection, like a third-party event type would.
+type trafficMetadata struct {
+	key     entityTracker.ConnectionKey
+	traffic entityTracker.Traffic
+}
+
+func (m *trafficMetadata) Connection() entityTracker.ConnectionKey { return m.key }
+func (m *trafficMetadata) Traffic() entityTracker.Traffic          { return m.traffic }
+
+// Test the lifecycle of a connection
+func TestConnectionTrackerLifecycle(t *testing.T) {
+	tracker := entityTracker.NewConnectionTracker()
+
+	conn, ok := tracker.Track(tcpEvent(100, 50000, state.TCP_CONNECT, 0))
+	if !ok || conn.State != state.CREATED || conn.Direction != entityModel.DIRECTION_OUTBOUND {
+		t.Fatalf("Failed to track connect: %+v", conn)
+	}
+
+	key := entityTracker.ConnectionKey{PID: 100, Protocol: 4, Saddr: "10.0.0.1", Sport: 50000, Daddr: "10.0.0.2", Dport: 443}
+	conn, ok = tracker.Track(&commonModel.CommonModel{Metadata: &trafficMetadata{
+		key:     key,
+		traffic: entityTracker.Traffic{SentOps: 2, SentBytes: 512, RecvOps: 1, RecvBytes: 4096},
+	}})
+	if !ok || conn.NumSentBytes != 512 || conn.NumRecvOps != 1 {
+		t.Fatalf("Failed to track traffic: %+v", conn)
+	}
+
+	// a disconnect from another process is matched by the 5-tuple
+	conn, ok = tracker.Track(tcpEvent(101, 50000, state.TCP_DISCONNECT, 5*time.Second))
+	if !ok || !conn.Closed() || conn.Duration != 5*time.Second || conn.PID != 100 || conn.State != state.MODIFIED {
+		t.Fatalf("Failed to track disconnect: %+v", conn)
+	}
+	if tracker.Len() != 0 {
+		t.Fatalf("Closed connection is still open")
+	}
+
+	conn, _ = tracker.Track(tcpEvent(200, 443, state.TCP_ACCEPT, 0))
+	if conn.Direction != entityModel.DIRECTION_INBOUND {
+		t.Fatalf("Accepted connection is not inbound: %v", conn.Direction)
+	}
+}
+
+// Test the connections reported by Sweep
+func TestConnectionTrackerSweep(t *testing.T) {
+	processes := entityTracker.NewProcessTracker()
+	tracker := entityTracker.NewConnectionTracker(
+		entityTracker.WithProcessTracker(processes),
+		entityTracker.WithLongLivedAfter(time.Minute),
+	)
+
+	processes.Track(processCreate(100, 1, "/usr/bin/curl", 0))
+	tracker.Track(tcpEvent(100, 50000, state.TCP_CONNECT, 0))
+	tracker.Track(tcpEvent(300, 50001, state.TCP_CONNECT, 50*time.Second))
+
+	if flagged := tracker.Sweep(baseTime.Add(55 * time.Second)); len(flagged) != 0 {
+		t.Fatalf("Sweep flagged young connections: %v", flagged)
+	}
+
+	processes.Track(processTerminate(100, 0, 56*time.Second))
+	flagged := tracker.Sweep(baseTime.Add(2 * time.Minute))
+	if len(flagged) != 2 {
+		t.Fatalf("Unexpected number of flagged connections: got %d, want 2", len(flagged))
+	}
+	if !flagged[0].Leaked || !flagged[0].LongLived || flagged[0].OwnerImage != "/usr/bin/curl" {
+		t.Fatalf("Connection of the exited process is not leaked: %+v", flagged[0])
+	}
+	// the owner of the second connection was never tracked, so it cannot leak
+	if flagged[1].Leaked || !flagged[1].LongLived {
+		t.Fatalf("Connection of an unknown process is not only long-lived: %+v", flagged[1])
+	}
+}
+
+// Test that returned connections do not share their rule matches with the tracker
+func TestConnectionTrackerCopies(t *testing.T) {
+	tracker := entityTracker.NewConnectionTracker(entityTracker.WithLongLivedAfter(time.Minute))
+	key := entityTracker.ConnectionKey{PID: 100, Protocol: 4, Saddr: "10.0.0.1", Sport: 50000, Daddr: "10.0.0.2", Dport: 443}
+	seeded := &entityModel.NetworkEntityModel{PID: 100, Protocol: 4, Saddr: "10.0.0.1", Sport: 50000, Daddr: "10.0.0.2", Dport: 443, StartTime: baseTime}
+	seeded.MatchedRuleIDs = commonModel.RuleMatches{{RuleID: "rule-1", MitreTechniques: []string{"T1071"}}}
+	tracker.Seed(seeded)
+
+	// mutate everything handed out or handed in
+	seeded.MatchedRuleIDs[0].RuleID = "seeded"
+	conn, _ := tracker.Lookup(key)
+	conn.MatchedRuleIDs[0].MitreTechniques[0] = "looked up"
+	conn, _ = tracker.Track(&commonModel.CommonModel{Metadata: &trafficMetadata{key: key, traffic: entityTracker.Traffic{SentOps: 1}}})
+	conn.MatchedRuleIDs[0].RuleID = "traffic"
+	swept := tracker.Sweep(baseTime.Add(time.Hour))
+	swept[0].MatchedRuleIDs[0].RuleID = "swept"
+
+	conn, _ = tracker.Lookup(key)
+	if match := conn.MatchedRuleIDs[0]; match.RuleID != "rule-1" || match.MitreTechniques[0] != "T1071" {
+		t.Fatalf("Tracked rule matches were modified through a copy: %+v", match)
+	}
+}
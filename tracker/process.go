@@ -0,0 +1,190 @@
+// tracker/process.go
+package entityTracker
+
+import (
+	"sync"
+	"time"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	entityModel "github.com/enki-polvo/polvo-logger/model/entity"
+	eventModel "github.com/enki-polvo/polvo-logger/model/event"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+// ProcessOption configures a ProcessTracker.
+type ProcessOption func(*ProcessTracker)
+
+// WithProcClock makes the tracker derive the ProcessKey and the start time of the created processes
+// whose event carries no ProcessKey from /proc, like ScanProc does.
+func WithProcClock(clock *ProcClock) ProcessOption {
+	return func(t *ProcessTracker) {
+		t.clock = clock
+	}
+}
+
+// ProcessTracker builds process entities from ProcessCreate and ProcessTerminate events.
+// It keeps one entity per live process, keyed by PID. It is safe for concurrent use.
+type ProcessTracker struct {
+	mu        sync.RWMutex
+	processes map[int64]*entityModel.ProcessEntityModel
+	clock     *ProcClock // optional, see WithProcClock
+}
+
+// NewProcessTracker returns an empty ProcessTracker.
+func NewProcessTracker(opts ...ProcessOption) *ProcessTracker {
+	t := &ProcessTracker{processes: map[int64]*entityModel.ProcessEntityModel{}}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Track updates the tracked processes with an event.
+// It accepts *eventModel.ProcessCreateEvent and *eventModel.ProcessTerminateEvent,
+// as well as *commonModel.CommonModel holding their metadata (e.g. allocated from the event pool).
+// It returns a copy of the updated entity, or false if the event is not a process event
+// or terminates a process that is not tracked.
+func (t *ProcessTracker) Track(event eventModel.Event) (*entityModel.ProcessEntityModel, bool) {
+	switch e := event.(type) {
+	case *eventModel.ProcessCreateEvent:
+		return t.create(e.Timestamp, &e.Metadata), true
+	case *eventModel.ProcessTerminateEvent:
+		return t.terminate(e.Timestamp, &e.Metadata)
+	case *commonModel.CommonModel:
+		switch metadata := e.Metadata.(type) {
+		case *eventModel.ProcessCreateMetadata:
+			return t.create(e.Timestamp, metadata), true
+		case *eventModel.ProcessTerminateMetadata:
+			return t.terminate(e.Timestamp, metadata)
+		}
+	}
+	return nil, false
+}
+
+// create starts tracking a process.
+// A create event for a PID that is already alive is an exec of a new image:
+// the entity keeps its start time and ProcessKey and becomes MODIFIED.
+// If the event carries another ProcessKey, the PID was reused after a missed terminate event
+// and a new entity replaces the old one.
+// Without ProcessKey in the event, the key comes from /proc with a ProcClock, or from the
+// event Timestamp if the process is not in /proc anymore or the tracker has no ProcClock.
+// Keys from the event Timestamp differ from the keys of ScanProc.
+func (t *ProcessTracker) create(timestamp time.Time, m *eventModel.ProcessCreateMetadata) *entityModel.ProcessEntityModel {
+	key, startTime := m.ProcessKey, timestamp
+	if key == "" && t.clock != nil {
+		if procKey, procStartTime, err := t.clock.ProcessKey(m.PID); err == nil {
+			key, startTime = procKey, procStartTime
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	process, isExists := t.processes[m.PID]
+	if isExists && key != "" && key != process.ProcessKey {
+		isExists = false
+	}
+	if isExists {
+		process.State = state.MODIFIED
+	} else {
+		process = &entityModel.ProcessEntityModel{
+			CommonEntityModel: entityModel.CommonEntityModel{
+				EntityType: entityModel.PROCESS_ENTITY,
+				State:      state.CREATED,
+			},
+			ProcessKey: key,
+			StartTime:  startTime,
+		}
+		if process.ProcessKey == "" {
+			process.ProcessKey = commonModel.NewProcessKey(m.PID, timestamp)
+		}
+		t.processes[m.PID] = process
+	}
+	process.PID = m.PID
+	process.PPID = m.PPID
+	process.TGID = m.TGID
+	process.UID = m.UID
+	process.Image = m.Image
+	process.Commandline = m.Commandline
+
+	return cloneProcess(process)
+}
+
+// terminate sets the exit time and code of a process and stops tracking it.
+func (t *ProcessTracker) terminate(timestamp time.Time, m *eventModel.ProcessTerminateMetadata) (*entityModel.ProcessEntityModel, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	process, isExists := t.processes[m.PID]
+	if !isExists {
+		return nil, false
+	}
+	delete(t.processes, m.PID)
+
+	process.State = state.MODIFIED
+	process.ExitTime = timestamp
+	process.ExitCode = m.Ret
+	return process, true
+}
+
+// Seed tracks processes that existed before the tracker, e.g. the Processes of an Inventory.
+// Processes that are already tracked are kept.
+func (t *ProcessTracker) Seed(processes ...*entityModel.ProcessEntityModel) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, process := range processes {
+		if _, isExists := t.processes[process.PID]; !isExists {
+			t.processes[process.PID] = cloneProcess(process)
+		}
+	}
+}
+
+// Lookup returns a copy of the live process with the given PID.
+func (t *ProcessTracker) Lookup(pid int64) (*entityModel.ProcessEntityModel, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	process, isExists := t.processes[pid]
+	if !isExists {
+		return nil, false
+	}
+	return cloneProcess(process), true
+}
+
+// ParentChain returns copies of the live ancestors of the process with the given PID,
+// starting with its parent. The chain ends at the first ancestor that is not tracked.
+func (t *ProcessTracker) ParentChain(pid int64) []*entityModel.ProcessEntityModel {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var chain []*entityModel.ProcessEntityModel
+	visited := map[int64]bool{pid: true}
+	process, isExists := t.processes[pid]
+	for isExists {
+		if visited[process.PPID] {
+			// a cycle left by missed terminate events
+			break
+		}
+		visited[process.PPID] = true
+
+		process, isExists = t.processes[process.PPID]
+		if isExists {
+			chain = append(chain, cloneProcess(process))
+		}
+	}
+	return chain
+}
+
+// cloneProcess returns a copy of the process entity that does not share its rule matches.
+func cloneProcess(process *entityModel.ProcessEntityModel) *entityModel.ProcessEntityModel {
+	return process.Clone().(*entityModel.ProcessEntityModel)
+}
+
+// Len returns the number of live processes.
+func (t *ProcessTracker) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return len(t.processes)
+}
@@ -0,0 +1,105 @@
+package entityTracker_test
+
+import (
+	"testing"
+	"time"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	entityModel "github.com/enki-polvo/polvo-logger/model/entity"
+	eventModel "github.com/enki-polvo/polvo-logger/model/event"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+	entityTracker "github.com/enki-polvo/polvo-logger/tracker"
+)
+
+var baseTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func processCreate(pid, ppid int64, image string, at time.Duration) *eventModel.ProcessCreateEvent {
+	return &eventModel.ProcessCreateEvent{
+		CommonHeader: commonModel.CommonHeader{EventCode: commonModel.PROC_CREATE, Timestamp: baseTime.Add(at)},
+		Metadata:     eventModel.ProcessCreateMetadata{PID: pid, PPID: ppid, TGID: pid, UID: 1000, Image: image, Commandline: image},
+	}
+}
+
+func processTerminate(pid, ret int64, at time.Duration) *eventModel.ProcessTerminateEvent {
+	return &eventModel.ProcessTerminateEvent{
+		CommonHeader: commonModel.CommonHeader{EventCode: commonModel.PROC_TERMINATE, Timestamp: baseTime.Add(at)},
+		Metadata:     eventModel.ProcessTerminateMetadata{PID: pid, Ret: ret},
+	}
+}
+
+// Test the lifecycle of a process entity
+func TestProcessTrackerLifecycle(t *testing.T) {
+	tracker := entityTracker.NewProcessTracker()
+
+	process, ok := tracker.Track(processCreate(100, 1, "/usr/bin/bash", 0))
+	if !ok || process.State != state.CREATED || !process.StartTime.Equal(baseTime) {
+		t.Fatalf("Failed to track process creation: %+v", process)
+	}
+
+	// an exec in the same process keeps the start time
+	process, _ = tracker.Track(&commonModel.CommonModel{
+		CommonHeader: commonModel.CommonHeader{Timestamp: baseTime.Add(time.Second)},
+		Metadata:     &eventModel.ProcessCreateMetadata{PID: 100, PPID: 1, Image: "/usr/bin/cat"},
+	})
+	if process.State != state.MODIFIED || process.Image != "/usr/bin/cat" || !process.StartTime.Equal(baseTime) {
+		t.Fatalf("Failed to track process exec: %+v", process)
+	}
+
+	process, ok = tracker.Track(processTerminate(100, 2, 5*time.Second))
+	if !ok || !process.Exited() || process.ExitCode != 2 || process.State != state.MODIFIED {
+		t.Fatalf("Failed to track process termination: %+v", process)
+	}
+	if _, ok = tracker.Lookup(100); ok {
+		t.Fatal("Terminated process is still tracked")
+	}
+	if _, ok = tracker.Track(processTerminate(100, 0, 6*time.Second)); ok {
+		t.Fatal("Termination of an unknown process was tracked")
+	}
+}
+
+// Test the parent chain of a process
+func TestProcessTrackerParentChain(t *testing.T) {
+	tracker := entityTracker.NewProcessTracker()
+	tracker.Track(processCreate(1, 0, "/sbin/init", 0))
+	tracker.Track(processCreate(200, 1, "/usr/sbin/sshd", 0))
+	tracker.Track(processCreate(300, 200, "/usr/bin/bash", 0))
+	tracker.Track(processCreate(400, 300, "/usr/bin/curl", 0))
+
+	chain := tracker.ParentChain(400)
+	want := []int64{300, 200, 1}
+	if len(chain) != len(want) {
+		t.Fatalf("Parent chain has unexpected length: got %d, want %d", len(chain), len(want))
+	}
+	for i, process := range chain {
+		if process.PID != want[i] {
+			t.Fatalf("Parent chain does not match at %d: got %v, want %v", i, process.PID, want[i])
+		}
+	}
+
+	// the chain ends at the first ancestor that is not tracked
+	tracker.Track(processTerminate(200, 0, time.Second))
+	if chain = tracker.ParentChain(400); len(chain) != 1 || chain[0].PID != 300 {
+		t.Fatalf("Parent chain did not end at the terminated ancestor: %v", chain)
+	}
+}
+
+// Test that returned processes do not share their rule matches with the tracker
+func TestProcessTrackerCopies(t *testing.T) {
+	tracker := entityTracker.NewProcessTracker()
+	seeded := &entityModel.ProcessEntityModel{ProcessKey: commonModel.NewProcessKey(1, baseTime), PID: 1, Image: "/sbin/init"}
+	seeded.MatchedRuleIDs = commonModel.RuleMatches{{RuleID: "rule-1", MitreTechniques: []string{"T1059"}}}
+	tracker.Seed(seeded)
+	tracker.Track(processCreate(100, 1, "/usr/bin/bash", 0))
+
+	// mutate everything handed out or handed in
+	seeded.MatchedRuleIDs[0].RuleID = "seeded"
+	process, _ := tracker.Lookup(1)
+	process.MatchedRuleIDs[0].MitreTechniques[0] = "looked up"
+	chain := tracker.ParentChain(100)
+	chain[0].MatchedRuleIDs[0].RuleID = "chained"
+
+	process, _ = tracker.Lookup(1)
+	if match := process.MatchedRuleIDs[0]; match.RuleID != "rule-1" || match.MitreTechniques[0] != "T1059" {
+		t.Fatalf("Tracked rule matches were modified through a copy: %+v", match)
+	}
+}
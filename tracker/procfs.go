@@ -0,0 +1,393 @@
+// tracker/procfs.go
+package entityTracker
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	entityModel "github.com/enki-polvo/polvo-logger/model/entity"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+const (
+	ErrMalformedProcFile = "malformed %s: %s"
+	ErrMalformedProcAddr = "malformed address: %q"
+)
+
+const (
+	// tcpListen and tcpTimeWait are the states of /proc/net/tcp entries that are not connections of a process.
+	tcpListen   = "0A"
+	tcpTimeWait = "06"
+	// statStartTimeField is the index of starttime in /proc/<pid>/stat, counted from the state after the comm.
+	statStartTimeField = 19
+	// atClockTicks is the type of the auxiliary vector entry holding the clock tick rate, AT_CLKTCK.
+	atClockTicks = 17
+)
+
+// netFiles are the connection tables of /proc/net with the IP version of their connections.
+var netFiles = []struct {
+	path     string
+	protocol int64
+}{
+	{"proc/net/tcp", 4},
+	{"proc/net/tcp6", 6},
+}
+
+// Inventory is the set of entities found by ScanProc.
+// Its entities have the State REUP, since they existed before they could be tracked.
+type Inventory struct {
+	ScannedAt   time.Time
+	Processes   []*entityModel.ProcessEntityModel
+	Connections []*entityModel.NetworkEntityModel
+}
+
+// ScanProc builds the inventory of the processes and TCP connections of the host whose
+// filesystem is mounted at root, from <root>/proc. Processes whose stat or status cannot be read,
+// e.g. because they exit during the scan, are skipped, and other fields that cannot be read
+// (e.g. the exe of another user's process) are left empty. Only errors reading /proc itself or its system-wide files fail the scan.
+// The StartTime of a connection is unknown and set to the time of the scan.
+func ScanProc(root string) (*Inventory, error) {
+	inventory := &Inventory{ScannedAt: time.Now()}
+
+	clock, err := NewProcClock(root)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, "proc"))
+	if err != nil {
+		return nil, err
+	}
+
+	processes := map[int64]*entityModel.ProcessEntityModel{}
+	sockets := map[string]int64{} // socket inode to PID
+	for _, entry := range entries {
+		pid, err := strconv.ParseInt(entry.Name(), 10, 64)
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, "proc", entry.Name())
+
+		process, err := readProcess(dir, pid, clock)
+		if err != nil {
+			// the process exited or became unreadable during the scan, e.g. ENOENT or ESRCH
+			continue
+		}
+		processes[pid] = process
+		inventory.Processes = append(inventory.Processes, process)
+
+		for _, inode := range readSocketInodes(dir) {
+			sockets[inode] = pid
+		}
+	}
+
+	for _, netFile := range netFiles {
+		connections, err := readConnections(filepath.Join(root, netFile.path), netFile.protocol, sockets)
+		if errors.Is(err, fs.ErrNotExist) {
+			// e.g. IPv6 is disabled
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, conn := range connections {
+			conn.StartTime = inventory.ScannedAt
+			if process, isExists := processes[conn.PID]; isExists {
+				conn.OwnerImage = process.Image
+			}
+			inventory.Connections = append(inventory.Connections, conn)
+		}
+	}
+	return inventory, nil
+}
+
+// Seed puts every entity of the inventory into the store.
+func (inv *Inventory) Seed(store *Store) {
+	for _, process := range inv.Processes {
+		store.Put(process, inv.ScannedAt)
+	}
+	for _, conn := range inv.Connections {
+		store.Put(conn, inv.ScannedAt)
+	}
+}
+
+// ProcClock converts the start times of /proc/<pid>/stat, in clock ticks since boot, to times.
+// It is the source of the ProcessKeys of ScanProc, and of a ProcessTracker created with
+// WithProcClock, so that both give a process the same ProcessKey.
+type ProcClock struct {
+	root       string
+	BootTime   time.Time
+	ClockTicks int64 // the USER_HZ of the kernel, the unit of the start times
+}
+
+// NewProcClock reads the boot time and the clock tick rate of the host whose filesystem is mounted at root.
+// The clock tick rate is read from the AT_CLKTCK entry of <root>/proc/self/auxv.
+func NewProcClock(root string) (*ProcClock, error) {
+	bootTime, err := readBootTime(filepath.Join(root, "proc/stat"))
+	if err != nil {
+		return nil, err
+	}
+	clockTicks, err := readClockTicks(filepath.Join(root, "proc/self/auxv"))
+	if err != nil {
+		return nil, err
+	}
+	return &ProcClock{root: root, BootTime: bootTime, ClockTicks: clockTicks}, nil
+}
+
+// StartTime returns the time of a start time in clock ticks since boot.
+func (c *ProcClock) StartTime(startTicks int64) time.Time {
+	seconds, ticks := startTicks/c.ClockTicks, startTicks%c.ClockTicks
+	return c.BootTime.Add(time.Duration(seconds)*time.Second + time.Duration(ticks)*time.Second/time.Duration(c.ClockTicks))
+}
+
+// ProcessKey returns the ProcessKey and the start time of the running process with the given PID.
+func (c *ProcClock) ProcessKey(pid int64) (commonModel.ProcessKey, time.Time, error) {
+	_, startTicks, err := readStat(filepath.Join(c.root, "proc", strconv.FormatInt(pid, 10), "stat"))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	startTime := c.StartTime(startTicks)
+	return commonModel.NewProcessKey(pid, startTime), startTime, nil
+}
+
+// readBootTime returns the boot time written in /proc/stat.
+func readBootTime(path string) (time.Time, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		value, isBootTime := strings.CutPrefix(scanner.Text(), "btime ")
+		if !isBootTime {
+			continue
+		}
+		seconds, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf(ErrMalformedProcFile, path, err)
+		}
+		return time.Unix(seconds, 0), nil
+	}
+	if err = scanner.Err(); err != nil {
+		return time.Time{}, err
+	}
+	return time.Time{}, fmt.Errorf(ErrMalformedProcFile, path, "no btime")
+}
+
+// readClockTicks returns the clock tick rate held by the AT_CLKTCK entry of an auxiliary vector,
+// made of pairs of native words: the type of the entry and its value.
+func readClockTicks(path string) (int64, error) {
+	auxv, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	word := strconv.IntSize / 8
+	readWord := func(b []byte) uint64 {
+		if word == 4 {
+			return uint64(binary.NativeEndian.Uint32(b))
+		}
+		return binary.NativeEndian.Uint64(b)
+	}
+	for i := 0; i+2*word <= len(auxv); i += 2 * word {
+		if readWord(auxv[i:]) != atClockTicks {
+			continue
+		}
+		clockTicks := readWord(auxv[i+word:])
+		if clockTicks == 0 || clockTicks > uint64(time.Second) {
+			return 0, fmt.Errorf(ErrMalformedProcFile, path, "invalid AT_CLKTCK")
+		}
+		return int64(clockTicks), nil
+	}
+	return 0, fmt.Errorf(ErrMalformedProcFile, path, "no AT_CLKTCK")
+}
+
+// readStat returns the parent PID and the start time in clock ticks since boot written in /proc/<pid>/stat.
+func readStat(path string) (ppid, startTicks int64, err error) {
+	stat, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	// the comm in parentheses may contain spaces and parentheses itself
+	end := strings.LastIndexByte(string(stat), ')')
+	if end < 0 {
+		return 0, 0, fmt.Errorf(ErrMalformedProcFile, path, "no comm")
+	}
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) <= statStartTimeField {
+		return 0, 0, fmt.Errorf(ErrMalformedProcFile, path, "too few fields")
+	}
+	if ppid, err = strconv.ParseInt(fields[1], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf(ErrMalformedProcFile, path, err)
+	}
+	if startTicks, err = strconv.ParseInt(fields[statStartTimeField], 10, 64); err != nil {
+		return 0, 0, fmt.Errorf(ErrMalformedProcFile, path, err)
+	}
+	return ppid, startTicks, nil
+}
+
+// readProcess reads the process entity of /proc/<pid>.
+func readProcess(dir string, pid int64, clock *ProcClock) (*entityModel.ProcessEntityModel, error) {
+	process := &entityModel.ProcessEntityModel{
+		CommonEntityModel: entityModel.CommonEntityModel{
+			EntityType: entityModel.PROCESS_ENTITY,
+			State:      state.REUP,
+		},
+		PID: pid,
+	}
+
+	ppid, startTicks, err := readStat(filepath.Join(dir, "stat"))
+	if err != nil {
+		return nil, err
+	}
+	process.PPID = ppid
+	process.StartTime = clock.StartTime(startTicks)
+	process.ProcessKey = commonModel.NewProcessKey(pid, process.StartTime)
+
+	statusPath := filepath.Join(dir, "status")
+	status, err := os.ReadFile(statusPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(status), "\n") {
+		key, value, _ := strings.Cut(line, ":")
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		switch key {
+		case "Tgid":
+			process.TGID, err = strconv.ParseInt(fields[0], 10, 64)
+		case "Uid":
+			// the real UID
+			process.UID, err = strconv.ParseInt(fields[0], 10, 64)
+		}
+		if err != nil {
+			return nil, fmt.Errorf(ErrMalformedProcFile, statusPath, err)
+		}
+	}
+
+	// the exe of kernel threads and of other users' processes cannot be read
+	process.Image, _ = os.Readlink(filepath.Join(dir, "exe"))
+	cmdline, _ := os.ReadFile(filepath.Join(dir, "cmdline"))
+	process.Commandline = strings.Join(strings.FieldsFunc(string(cmdline), func(r rune) bool { return r == 0 }), " ")
+	return process, nil
+}
+
+// readSocketInodes returns the inodes of the sockets opened by the process of /proc/<pid>.
+func readSocketInodes(dir string) []string {
+	fds, err := os.ReadDir(filepath.Join(dir, "fd"))
+	if err != nil {
+		return nil
+	}
+
+	var inodes []string
+	for _, fd := range fds {
+		link, err := os.Readlink(filepath.Join(dir, "fd", fd.Name()))
+		if err != nil {
+			continue
+		}
+		if inode, isSocket := strings.CutPrefix(link, "socket:["); isSocket {
+			inodes = append(inodes, strings.TrimSuffix(inode, "]"))
+		}
+	}
+	return inodes
+}
+
+// readConnections reads the connections of a /proc/net/tcp{,6} table.
+// Connections to a local port that is listening are inbound, the others are outbound.
+func readConnections(path string, protocol int64, sockets map[string]int64) ([]*entityModel.NetworkEntityModel, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var connections []*entityModel.NetworkEntityModel
+	listening := map[int64]bool{}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // the header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			return nil, fmt.Errorf(ErrMalformedProcFile, path, "too few fields")
+		}
+		saddr, sport, err := parseProcAddr(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf(ErrMalformedProcFile, path, err)
+		}
+		daddr, dport, err := parseProcAddr(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf(ErrMalformedProcFile, path, err)
+		}
+
+		switch fields[3] {
+		case tcpListen:
+			listening[sport] = true
+			continue
+		case tcpTimeWait:
+			continue
+		}
+
+		connections = append(connections, &entityModel.NetworkEntityModel{
+			CommonEntityModel: entityModel.CommonEntityModel{
+				EntityType: entityModel.NETWORK_ENTITY,
+				State:      state.REUP,
+			},
+			PID:      sockets[fields[9]],
+			Protocol: protocol,
+			Saddr:    saddr,
+			Sport:    sport,
+			Daddr:    daddr,
+			Dport:    dport,
+		})
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, conn := range connections {
+		conn.Direction = entityModel.DIRECTION_OUTBOUND
+		if listening[conn.Sport] {
+			conn.Direction = entityModel.DIRECTION_INBOUND
+		}
+	}
+	return connections, nil
+}
+
+// parseProcAddr parses an "address:port" of /proc/net/tcp{,6}.
+// The address is hexadecimal in groups of 32-bit words in host byte order (little-endian),
+// the port is hexadecimal.
+func parseProcAddr(text string) (string, int64, error) {
+	addrHex, portHex, _ := strings.Cut(text, ":")
+	port, err := strconv.ParseInt(portHex, 16, 64)
+	if err != nil {
+		return "", 0, err
+	}
+	b, err := hex.DecodeString(addrHex)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(b) != 4 && len(b) != 16 {
+		return "", 0, fmt.Errorf(ErrMalformedProcAddr, addrHex)
+	}
+	for i := 0; i < len(b); i += 4 {
+		b[i], b[i+1], b[i+2], b[i+3] = b[i+3], b[i+2], b[i+1], b[i]
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr.Unmap().String(), port, nil
+}
@@ -0,0 +1,229 @@
+package entityTracker_test
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	entityModel "github.com/enki-polvo/polvo-logger/model/entity"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+	entityTracker "github.com/enki-polvo/polvo-logger/tracker"
+)
+
+const fakeTcpHeader = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+
+// writeFakeFile writes content to root/path, creating its directories.
+func writeFakeFile(t *testing.T, root, path, content string) {
+	t.Helper()
+	full := filepath.Join(root, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+}
+
+// writeFakeLink creates the symbolic link root/path pointing to target, creating its directories.
+func writeFakeLink(t *testing.T, root, path, target string) {
+	t.Helper()
+	full := filepath.Join(root, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.Symlink(target, full); err != nil {
+		t.Fatalf("Failed to create link: %v", err)
+	}
+}
+
+// writeFakeAuxv writes root/proc/self/auxv holding the clock tick rate, between two other entries.
+func writeFakeAuxv(t *testing.T, root string, clockTicks uint64) {
+	t.Helper()
+	var auxv []byte
+	for _, entry := range [][2]uint64{{6, 4096}, {17, clockTicks}, {0, 0}} {
+		for _, word := range entry {
+			if strconv.IntSize == 32 {
+				auxv = binary.NativeEndian.AppendUint32(auxv, uint32(word))
+			} else {
+				auxv = binary.NativeEndian.AppendUint64(auxv, word)
+			}
+		}
+	}
+	writeFakeFile(t, root, "proc/self/auxv", string(auxv))
+}
+
+// writeFakeProcFS writes a procfs tree with init, a python server with two connections, and one unowned IPv6 connection.
+func writeFakeProcFS(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFakeFile(t, root, "proc/stat", "cpu  1 2 3 4\nbtime 1735689600\nprocesses 100\n")
+	writeFakeAuxv(t, root, 100)
+
+	writeFakeFile(t, root, "proc/1/stat", "1 (systemd) S 0 1 1 0 -1 4194560 100 0 0 0 1 2 0 0 20 0 1 0 10 12345678 200\n")
+	writeFakeFile(t, root, "proc/1/status", "Name:\tsystemd\nTgid:\t1\nPid:\t1\nUid:\t0\t0\t0\t0\n")
+	writeFakeFile(t, root, "proc/1/cmdline", "/sbin/init\x00")
+	writeFakeLink(t, root, "proc/1/exe", "/usr/lib/systemd/systemd")
+
+	writeFakeFile(t, root, "proc/1234/stat", "1234 (my (server)) S 1 1234 1234 0 -1 4194560 100 0 0 0 1 2 0 0 20 0 1 0 500 12345678 200\n")
+	writeFakeFile(t, root, "proc/1234/status", "Name:\tpython3\nTgid:\t1234\nPid:\t1234\nUid:\t1000\t1000\t1000\t1000\n")
+	writeFakeFile(t, root, "proc/1234/cmdline", "python3\x00server.py\x00")
+	writeFakeLink(t, root, "proc/1234/exe", "/usr/bin/python3")
+	writeFakeLink(t, root, "proc/1234/fd/3", "socket:[100]")
+	writeFakeLink(t, root, "proc/1234/fd/4", "socket:[111]")
+	writeFakeLink(t, root, "proc/1234/fd/5", "socket:[222]")
+	writeFakeLink(t, root, "proc/1234/fd/6", "/var/log/server.log")
+
+	writeFakeFile(t, root, "proc/net/tcp", fakeTcpHeader+
+		"   0: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 100 1 0 100 0 0 10 0\n"+
+		"   1: 0100007F:1F90 0100007F:C350 01 00000000:00000000 00:00000000 00000000  1000        0 111 1 0 20 4 30 10 -1\n"+
+		"   2: 0100000A:C351 0200000A:01BB 01 00000000:00000000 00:00000000 00000000  1000        0 222 1 0 20 4 30 10 -1\n"+
+		"   3: 0100000A:C352 0200000A:01BB 06 00000000:00000000 00:00000000 00000000     0        0 0 1 0 20 4 30 10 -1\n")
+	writeFakeFile(t, root, "proc/net/tcp6", fakeTcpHeader+
+		"   0: 00000000000000000000000001000000:D431 00000000000000000000000001000000:0016 01 00000000:00000000 00:00000000 00000000     0        0 333 1 0 20 4 30 10 -1\n")
+	return root
+}
+
+// Test the inventory of a fake procfs tree
+func TestScanProc(t *testing.T) {
+	inventory, err := entityTracker.ScanProc(writeFakeProcFS(t))
+	if err != nil {
+		t.Fatalf("Failed to scan procfs: %v", err)
+	}
+
+	if len(inventory.Processes) != 2 {
+		t.Fatalf("Unexpected number of processes: got %d, want 2", len(inventory.Processes))
+	}
+	var server *entityModel.ProcessEntityModel
+	for _, process := range inventory.Processes {
+		if process.PID == 1234 {
+			server = process
+		}
+	}
+	bootTime := time.Unix(1735689600, 0)
+	if server == nil || server.PPID != 1 || server.UID != 1000 || server.TGID != 1234 ||
+		server.Image != "/usr/bin/python3" || server.Commandline != "python3 server.py" ||
+		!server.StartTime.Equal(bootTime.Add(5*time.Second)) || server.State != state.REUP {
+		t.Fatalf("Scanned process does not match: %+v", server)
+	}
+
+	want := map[string]entityModel.Direction{
+		"1234/4/127.0.0.1:8080-127.0.0.1:50000": entityModel.DIRECTION_INBOUND,
+		"1234/4/10.0.0.1:50001-10.0.0.2:443":    entityModel.DIRECTION_OUTBOUND,
+		"0/6/[::1]:54321-[::1]:22":              entityModel.DIRECTION_OUTBOUND,
+	}
+	if len(inventory.Connections) != len(want) {
+		t.Fatalf("Unexpected number of connections: got %d, want %d", len(inventory.Connections), len(want))
+	}
+	for _, conn := range inventory.Connections {
+		direction, isExists := want[conn.EntityID()]
+		if !isExists || conn.Direction != direction || conn.State != state.REUP {
+			t.Fatalf("Scanned connection does not match: %v %+v", conn.EntityID(), conn)
+		}
+		if conn.PID == 1234 && conn.OwnerImage != "/usr/bin/python3" {
+			t.Fatalf("Scanned connection has no owner: %+v", conn)
+		}
+	}
+}
+
+// Test the inventory of a procfs tree with processes that cannot be read
+// Test that such processes are skipped instead of failing the scan
+func TestScanProcSkipsUnreadableProcesses(t *testing.T) {
+	root := writeFakeProcFS(t)
+	// a stat that cannot be read as a file, a malformed stat, and a process without status
+	if err := os.MkdirAll(filepath.Join(root, "proc/2000/stat"), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	writeFakeFile(t, root, "proc/2001/stat", "2001 (zombie\n")
+	writeFakeFile(t, root, "proc/2002/stat", "2002 (exiting) S 1 2002 2002 0 -1 4194560 100 0 0 0 1 2 0 0 20 0 1 0 500 12345678 200\n")
+
+	inventory, err := entityTracker.ScanProc(root)
+	if err != nil {
+		t.Fatalf("Failed to scan procfs: %v", err)
+	}
+	if len(inventory.Processes) != 2 {
+		t.Fatalf("Unexpected number of processes: got %d, want 2", len(inventory.Processes))
+	}
+	for _, process := range inventory.Processes {
+		if process.PID != 1 && process.PID != 1234 {
+			t.Fatalf("Unreadable process was not skipped: %+v", process)
+		}
+	}
+}
+
+// Test seeding the store and the trackers with an inventory
+func TestSeedInventory(t *testing.T) {
+	inventory, err := entityTracker.ScanProc(writeFakeProcFS(t))
+	if err != nil {
+		t.Fatalf("Failed to scan procfs: %v", err)
+	}
+
+	store := entityTracker.NewStore()
+	inventory.Seed(store)
+	if store.Len() != 5 {
+		t.Fatalf("Unexpected number of stored entities: got %d, want 5", store.Len())
+	}
+
+	processes := entityTracker.NewProcessTracker()
+	processes.Seed(inventory.Processes...)
+	connections := entityTracker.NewConnectionTracker(entityTracker.WithProcessTracker(processes))
+	connections.Seed(inventory.Connections...)
+
+	// the seeded process and connection pair with the events that follow
+	if process, ok := processes.Track(processTerminate(1234, 0, 0)); !ok || process.Image != "/usr/bin/python3" {
+		t.Fatalf("Failed to terminate the seeded process: %+v", process)
+	}
+	if leaked := connections.Sweep(inventory.ScannedAt); len(leaked) != 2 || !leaked[0].Leaked {
+		t.Fatalf("Connections of the terminated seeded process are not leaked: %v", leaked)
+	}
+}
+
+// Test that the start times are converted with the clock tick rate of the host
+func TestProcClock(t *testing.T) {
+	root := writeFakeProcFS(t)
+	writeFakeAuxv(t, root, 250)
+
+	clock, err := entityTracker.NewProcClock(root)
+	if err != nil {
+		t.Fatalf("Failed to read proc clock: %v", err)
+	}
+	bootTime := time.Unix(1735689600, 0)
+	if clock.ClockTicks != 250 || !clock.StartTime(625).Equal(bootTime.Add(2500*time.Millisecond)) {
+		t.Fatalf("Proc clock does not match: %+v", clock)
+	}
+
+	writeFakeFile(t, root, "proc/self/auxv", "")
+	if _, err = entityTracker.NewProcClock(root); err == nil {
+		t.Fatal("Expected an error due to missing AT_CLKTCK, but got none")
+	}
+}
+
+// Test that the tracker keys a created process like the scan of /proc
+func TestProcessTrackerProcClock(t *testing.T) {
+	root := writeFakeProcFS(t)
+	inventory, err := entityTracker.ScanProc(root)
+	if err != nil {
+		t.Fatalf("Failed to scan procfs: %v", err)
+	}
+	clock, err := entityTracker.NewProcClock(root)
+	if err != nil {
+		t.Fatalf("Failed to read proc clock: %v", err)
+	}
+
+	// the create event is seen well after the process started
+	processes := entityTracker.NewProcessTracker(entityTracker.WithProcClock(clock))
+	created, _ := processes.Track(processCreate(1234, 1, "/usr/bin/python3", time.Minute))
+	for _, scanned := range inventory.Processes {
+		if scanned.PID == 1234 && (created.ProcessKey != scanned.ProcessKey || !created.StartTime.Equal(scanned.StartTime)) {
+			t.Fatalf("Tracked process key does not match the scan: got %v, want %v", created.ProcessKey, scanned.ProcessKey)
+		}
+	}
+
+	// processes that are not in /proc anymore are keyed by the event timestamp
+	exited, _ := processes.Track(processCreate(4321, 1, "/usr/bin/true", 0))
+	if !exited.StartTime.Equal(baseTime) {
+		t.Fatalf("Exited process start time does not match: %+v", exited)
+	}
+}
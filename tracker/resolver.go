@@ -0,0 +1,142 @@
+// tracker/resolver.go
+package entityTracker
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	entityModel "github.com/enki-polvo/polvo-logger/model/entity"
+	eventModel "github.com/enki-polvo/polvo-logger/model/event"
+)
+
+// processInstance is the lifetime of a process instance.
+type processInstance struct {
+	key   commonModel.ProcessKey
+	start time.Time
+	exit  time.Time // zero while alive
+}
+
+// ProcessResolver maps the PID and the Timestamp of an event to the process instance
+// that had the PID at that time, so events of processes that reused a PID are told apart.
+// Exited instances are kept until they are pruned, so late events still resolve.
+// It is safe for concurrent use.
+type ProcessResolver struct {
+	mu        sync.RWMutex
+	instances map[int64][]processInstance // per PID, sorted by start time
+}
+
+// NewProcessResolver returns an empty ProcessResolver.
+func NewProcessResolver() *ProcessResolver {
+	return &ProcessResolver{instances: map[int64][]processInstance{}}
+}
+
+// Observe records the lifetime of a process entity,
+// e.g. returned by ProcessTracker.Track or found by ScanProc.
+func (r *ProcessResolver) Observe(process *entityModel.ProcessEntityModel) {
+	key := commonModel.ProcessKey(process.EntityID())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	instances := r.instances[process.PID]
+	for i := range instances {
+		if instances[i].key == key {
+			instances[i].exit = process.ExitTime
+			return
+		}
+	}
+
+	i, _ := slices.BinarySearchFunc(instances, process.StartTime, func(instance processInstance, start time.Time) int {
+		return instance.start.Compare(start)
+	})
+	r.instances[process.PID] = slices.Insert(instances, i, processInstance{
+		key:   key,
+		start: process.StartTime,
+		exit:  process.ExitTime,
+	})
+}
+
+// Resolve returns the ProcessKey of the process instance that had the PID at the given time.
+func (r *ProcessResolver) Resolve(pid int64, at time.Time) (commonModel.ProcessKey, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	instances := r.instances[pid]
+	for i := len(instances) - 1; i >= 0; i-- {
+		instance := instances[i]
+		if instance.start.After(at) {
+			continue
+		}
+		if instance.exit.IsZero() || !at.After(instance.exit) {
+			return instance.key, true
+		}
+		// the latest instance started before at has exited before at
+		break
+	}
+	return "", false
+}
+
+// ResolveEvent returns the ProcessKey of the process instance that produced the event.
+// It accepts the events whose metadata identify their process by PID, e.g. *eventModel.TcpEvent,
+// *eventModel.FileOpenEvent and *eventModel.BashReadlineEvent, as well as *commonModel.CommonModel holding their metadata.
+func (r *ProcessResolver) ResolveEvent(event eventModel.Event) (commonModel.ProcessKey, bool) {
+	var header *commonModel.CommonHeader
+	var metadata any
+
+	switch e := event.(type) {
+	case *eventModel.TcpEvent:
+		header, metadata = &e.CommonHeader, &e.Metadata
+	case *eventModel.FileOpenEvent:
+		header, metadata = &e.CommonHeader, &e.Metadata
+	case *eventModel.FileRenameEvent:
+		header, metadata = &e.CommonHeader, &e.Metadata
+	case *eventModel.BashReadlineEvent:
+		header, metadata = &e.CommonHeader, &e.Metadata
+	case *eventModel.ServiceEvent:
+		header, metadata = &e.CommonHeader, &e.Metadata
+	case *commonModel.CommonModel:
+		header, metadata = &e.CommonHeader, e.Metadata
+	default:
+		return "", false
+	}
+
+	var pid int64
+	switch m := metadata.(type) {
+	case *eventModel.TcpMetadata:
+		pid = m.PID
+	case *eventModel.FileOpenMetadata:
+		pid = m.PID
+	case *eventModel.FileRenameMetadata:
+		pid = m.PID
+	case *eventModel.BashReadlineMetadata:
+		pid = m.PID
+	case *eventModel.ServiceMetadata:
+		pid = m.PID
+	default:
+		return "", false
+	}
+	return r.Resolve(pid, header.Timestamp)
+}
+
+// Prune forgets the process instances that exited before the given time.
+// It returns the number of forgotten instances.
+func (r *ProcessResolver) Prune(before time.Time) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pruned := 0
+	for pid, instances := range r.instances {
+		kept := slices.DeleteFunc(instances, func(instance processInstance) bool {
+			return !instance.exit.IsZero() && instance.exit.Before(before)
+		})
+		pruned += len(instances) - len(kept)
+		if len(kept) == 0 {
+			delete(r.instances, pid)
+		} else {
+			r.instances[pid] = kept
+		}
+	}
+	return pruned
+}
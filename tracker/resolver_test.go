@@ -0,0 +1,70 @@
+package entityTracker_test
+
+import (
+	"testing"
+	"time"
+
+	commonModel "github.com/enki-polvo/polvo-logger/model"
+	eventModel "github.com/enki-polvo/polvo-logger/model/event"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+	entityTracker "github.com/enki-polvo/polvo-logger/tracker"
+)
+
+// Test that a reused PID is resolved to the process instance alive at the time of the event
+func TestProcessResolverPIDReuse(t *testing.T) {
+	processes := entityTracker.NewProcessTracker()
+	resolver := entityTracker.NewProcessResolver()
+	track := func(event eventModel.Event) commonModel.ProcessKey {
+		process, ok := processes.Track(event)
+		if !ok {
+			t.Fatalf("Failed to track process event: %+v", event)
+		}
+		resolver.Observe(process)
+		return process.ProcessKey
+	}
+
+	first := track(processCreate(100, 1, "/usr/bin/curl", 0))
+	track(processTerminate(100, 0, 10*time.Second))
+	second := track(processCreate(100, 1, "/usr/bin/nc", 20*time.Second))
+	if first == second {
+		t.Fatalf("Processes reusing a PID have the same key: %v", first)
+	}
+
+	tests := []struct {
+		at      time.Duration
+		want    commonModel.ProcessKey
+		isFound bool
+	}{
+		{at: 5 * time.Second, want: first, isFound: true},
+		{at: 10 * time.Second, want: first, isFound: true},
+		{at: 15 * time.Second, isFound: false},
+		{at: time.Minute, want: second, isFound: true},
+	}
+	for _, test := range tests {
+		// a late event is resolved by its timestamp, not by its arrival
+		key, ok := resolver.ResolveEvent(tcpEvent(100, 50000, state.TCP_CONNECT, test.at))
+		if ok != test.isFound || key != test.want {
+			t.Fatalf("Resolved event at %v does not match: got %v %v, want %v %v", test.at, key, ok, test.want, test.isFound)
+		}
+	}
+
+	if pruned := resolver.Prune(baseTime.Add(time.Minute)); pruned != 1 {
+		t.Fatalf("Unexpected number of pruned instances: got %d, want 1", pruned)
+	}
+	if _, ok := resolver.Resolve(100, baseTime.Add(5*time.Second)); ok {
+		t.Fatal("Pruned instance is still resolved")
+	}
+}
+
+// Test that a create event with another ProcessKey replaces a process that missed its terminate event
+func TestProcessTrackerMissedTerminate(t *testing.T) {
+	processes := entityTracker.NewProcessTracker()
+	first, _ := processes.Track(processCreate(100, 1, "/usr/bin/curl", 0))
+
+	event := processCreate(100, 1, "/usr/bin/nc", time.Second)
+	event.Metadata.ProcessKey = commonModel.NewProcessKey(100, event.Timestamp)
+	second, _ := processes.Track(event)
+	if second.State != state.CREATED || second.ProcessKey == first.ProcessKey || !second.StartTime.Equal(event.Timestamp) {
+		t.Fatalf("Reused PID did not create a new process: %+v", second)
+	}
+}
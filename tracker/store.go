@@ -0,0 +1,226 @@
+// tracker/store.go
+package entityTracker
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	entityModel "github.com/enki-polvo/polvo-logger/model/entity"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+)
+
+const (
+	// DEFAULT_TTL is the default time terminated entities are kept after their last update.
+	DEFAULT_TTL = 5 * time.Minute
+	// DEFAULT_IDLE_TTL is the default time entities are kept without update.
+	// It bounds the Store for entities that never terminate, such as files.
+	DEFAULT_IDLE_TTL = 24 * time.Hour
+	// DEFAULT_REUP_INTERVAL is the default interval at which surviving entities are exported again.
+	DEFAULT_REUP_INTERVAL = time.Hour
+	// DEFAULT_SWEEP_INTERVAL is the default interval at which Run evicts and re-exports entities.
+	DEFAULT_SWEEP_INTERVAL = time.Minute
+)
+
+// storeKey identifies an entity in the Store.
+type storeKey struct {
+	entityType entityModel.EntityType
+	id         string
+}
+
+// storedEntity is an entity with the bookkeeping of the Store.
+type storedEntity struct {
+	entity       entityModel.Entity
+	lastSeen     time.Time // the last time the entity was put
+	lastExported time.Time // the last time the entity was put or re-exported
+}
+
+// StoreOption configures a Store.
+type StoreOption func(*Store)
+
+// WithTTL sets how long terminated entities are kept after their last update.
+func WithTTL(ttl time.Duration) StoreOption {
+	return func(s *Store) {
+		s.ttl = ttl
+	}
+}
+
+// WithIdleTTL sets how long entities that are not terminated are kept without update.
+// The default is DEFAULT_IDLE_TTL. Zero keeps them until they terminate, which lets
+// entities that never terminate, such as files, grow the Store without bound.
+func WithIdleTTL(ttl time.Duration) StoreOption {
+	return func(s *Store) {
+		s.idleTTL = ttl
+	}
+}
+
+// WithReupInterval sets the interval after which a surviving entity is exported again as REUP.
+func WithReupInterval(interval time.Duration) StoreOption {
+	return func(s *Store) {
+		s.reupInterval = interval
+	}
+}
+
+// WithSweepInterval sets the interval at which Run evicts and re-exports entities.
+func WithSweepInterval(interval time.Duration) StoreOption {
+	return func(s *Store) {
+		s.sweepInterval = interval
+	}
+}
+
+// Store keeps entities in memory, keyed by EntityType and EntityID.
+// Terminated or idle entities are evicted after their TTL, and entities surviving
+// for longer than the REUP interval are exported again with State REUP,
+// so downstream stores that age data out still see them.
+// It is safe for concurrent use.
+type Store struct {
+	mu            sync.Mutex
+	entities      map[storeKey]*storedEntity
+	ttl           time.Duration
+	idleTTL       time.Duration
+	reupInterval  time.Duration
+	sweepInterval time.Duration
+}
+
+// NewStore returns an empty Store.
+func NewStore(opts ...StoreOption) *Store {
+	s := &Store{
+		entities:      map[storeKey]*storedEntity{},
+		ttl:           DEFAULT_TTL,
+		idleTTL:       DEFAULT_IDLE_TTL,
+		reupInterval:  DEFAULT_REUP_INTERVAL,
+		sweepInterval: DEFAULT_SWEEP_INTERVAL,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Put stores a copy of the entity, replacing the entity with the same EntityType and EntityID.
+// now is the time the entity was updated and exported.
+func (s *Store) Put(entity entityModel.Entity, now time.Time) {
+	key := storeKey{entityType: entity.Common().EntityType, id: entity.EntityID()}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entities[key] = &storedEntity{entity: entity.Clone(), lastSeen: now, lastExported: now}
+}
+
+// Update stores a copy of the entity like Put, and returns the change record of the entity
+// against its stored version, caused by the event with the given ID.
+// It returns nil if the entity was not stored yet or no field changed.
+func (s *Store) Update(entity entityModel.Entity, now time.Time, eventID string) (*entityModel.ChangeRecord, error) {
+	key := storeKey{entityType: entity.Common().EntityType, id: entity.EntityID()}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, isExists := s.entities[key]
+	s.entities[key] = &storedEntity{entity: entity.Clone(), lastSeen: now, lastExported: now}
+	if !isExists {
+		return nil, nil
+	}
+	return entityModel.NewChangeRecord(stored.entity, entity, now, eventID)
+}
+
+// Get returns a copy of the entity with the given EntityType and EntityID.
+func (s *Store) Get(entityType entityModel.EntityType, id string) (entityModel.Entity, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, isExists := s.entities[storeKey{entityType: entityType, id: id}]
+	if !isExists {
+		return nil, false
+	}
+	return stored.entity.Clone(), true
+}
+
+// Delete removes the entity with the given EntityType and EntityID.
+func (s *Store) Delete(entityType entityModel.EntityType, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entities, storeKey{entityType: entityType, id: id})
+}
+
+// Len returns the number of stored entities.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.entities)
+}
+
+// Evict removes the terminated entities not updated within the TTL, and the entities
+// not updated within the idle TTL if it is set. It returns the evicted entities.
+func (s *Store) Evict(now time.Time) []entityModel.Entity {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var evicted []entityModel.Entity
+	for key, stored := range s.entities {
+		age := now.Sub(stored.lastSeen)
+		terminated := stored.entity.Terminated() && age >= s.ttl
+		idle := s.idleTTL > 0 && age >= s.idleTTL
+		if terminated || idle {
+			delete(s.entities, key)
+			evicted = append(evicted, stored.entity)
+		}
+	}
+	sortEntities(evicted)
+	return evicted
+}
+
+// Reup returns copies of the entities that are not terminated and were not exported
+// within the REUP interval, with their State set to REUP, to be exported again.
+// The stored entities keep their State.
+func (s *Store) Reup(now time.Time) []entityModel.Entity {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var reup []entityModel.Entity
+	for _, stored := range s.entities {
+		if stored.entity.Terminated() || now.Sub(stored.lastExported) < s.reupInterval {
+			continue
+		}
+		stored.lastExported = now
+		clone := stored.entity.Clone()
+		clone.Common().State = state.REUP
+		reup = append(reup, clone)
+	}
+	sortEntities(reup)
+	return reup
+}
+
+// Run evicts and re-exports entities at the sweep interval until ctx is done.
+// export is called with every entity set to REUP.
+func (s *Store) Run(ctx context.Context, export func(entityModel.Entity)) {
+	ticker := time.NewTicker(s.sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.Evict(now)
+			for _, entity := range s.Reup(now) {
+				export(entity)
+			}
+		}
+	}
+}
+
+// sortEntities sorts entities by EntityType and EntityID.
+func sortEntities(entities []entityModel.Entity) {
+	slices.SortFunc(entities, func(a, b entityModel.Entity) int {
+		if a.Common().EntityType != b.Common().EntityType {
+			return int(a.Common().EntityType - b.Common().EntityType)
+		}
+		return strings.Compare(a.EntityID(), b.EntityID())
+	})
+}
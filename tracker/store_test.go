@@ -0,0 +1,135 @@
+package entityTracker_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	entityModel "github.com/enki-polvo/polvo-logger/model/entity"
+	state "github.com/enki-polvo/polvo-logger/model/state"
+	entityTracker "github.com/enki-polvo/polvo-logger/tracker"
+)
+
+// Test eviction and re-export of stored entities
+func TestStoreEvictAndReup(t *testing.T) {
+	store := entityTracker.NewStore(
+		entityTracker.WithTTL(time.Minute),
+		entityTracker.WithReupInterval(time.Hour),
+	)
+	processes := entityTracker.NewProcessTracker()
+
+	longLived, _ := processes.Track(processCreate(1, 0, "/sbin/init", 0))
+	store.Put(longLived, baseTime)
+	shortLived, _ := processes.Track(processCreate(100, 1, "/usr/bin/ls", 0))
+	store.Put(shortLived, baseTime)
+	shortLived, _ = processes.Track(processTerminate(100, 0, time.Second))
+	store.Put(shortLived, baseTime.Add(time.Second))
+
+	if evicted := store.Evict(baseTime.Add(30 * time.Second)); len(evicted) != 0 {
+		t.Fatalf("Evicted entities before their TTL: %v", evicted)
+	}
+	evicted := store.Evict(baseTime.Add(2 * time.Minute))
+	if len(evicted) != 1 || evicted[0].EntityID() != string(shortLived.ProcessKey) {
+		t.Fatalf("Failed to evict the terminated process: %v", evicted)
+	}
+
+	if reup := store.Reup(baseTime.Add(30 * time.Minute)); len(reup) != 0 {
+		t.Fatalf("Re-exported entities before the REUP interval: %v", reup)
+	}
+	reup := store.Reup(baseTime.Add(time.Hour))
+	if len(reup) != 1 || reup[0].Common().State != state.REUP {
+		t.Fatalf("Failed to re-export the long-lived process: %v", reup)
+	}
+	// the next REUP is an interval after the last export
+	if reup = store.Reup(baseTime.Add(90 * time.Minute)); len(reup) != 0 {
+		t.Fatalf("Re-exported entity again within the REUP interval: %v", reup)
+	}
+
+	// the stored process keeps its State, only the exported copies are REUP
+	stored, ok := store.Get(entityModel.PROCESS_ENTITY, longLived.EntityID())
+	if !ok || stored.Common().State != longLived.State {
+		t.Fatalf("Failed to get the re-exported process with its State: %v", stored)
+	}
+}
+
+// Test the idle TTL of entities that never terminate
+func TestStoreIdleTTL(t *testing.T) {
+	store := entityTracker.NewStore(entityTracker.WithIdleTTL(10 * time.Minute))
+	files := entityTracker.NewFileAggregator()
+
+	file, _ := files.Track(fileOpen(100, 42, "/etc/passwd", state.FILE_OPEN_TO_READ))
+	store.Put(file, baseTime)
+
+	if evicted := store.Evict(baseTime.Add(10 * time.Minute)); len(evicted) != 1 {
+		t.Fatalf("Failed to evict the idle file: %v", evicted)
+	}
+
+	// files are evicted by default, since they never terminate
+	store = entityTracker.NewStore()
+	store.Put(file, baseTime)
+	if evicted := store.Evict(baseTime.Add(entityTracker.DEFAULT_IDLE_TTL - time.Minute)); len(evicted) != 0 {
+		t.Fatalf("Evicted the file before the default idle TTL: %v", evicted)
+	}
+	if evicted := store.Evict(baseTime.Add(entityTracker.DEFAULT_IDLE_TTL)); len(evicted) != 1 {
+		t.Fatalf("Failed to evict the idle file by default: %v", evicted)
+	}
+}
+
+// Test that Run exports REUP entities periodically
+func TestStoreRun(t *testing.T) {
+	store := entityTracker.NewStore(
+		entityTracker.WithReupInterval(time.Millisecond),
+		entityTracker.WithSweepInterval(time.Millisecond),
+	)
+	process, _ := entityTracker.NewProcessTracker().Track(processCreate(1, 0, "/sbin/init", 0))
+	store.Put(process, time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var once sync.Once
+	exported := make(chan entityModel.Entity)
+	done := make(chan struct{})
+	go func() {
+		store.Run(ctx, func(entity entityModel.Entity) {
+			once.Do(func() { exported <- entity })
+		})
+		close(done)
+	}()
+
+	select {
+	case entity := <-exported:
+		if entity.Common().State != state.REUP {
+			t.Fatalf("Exported entity is not REUP: %v", entity.Common().State)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not export the entity")
+	}
+	cancel()
+	<-done
+}
+
+// Test the change records of entities updated in the store
+func TestStoreUpdate(t *testing.T) {
+	store := entityTracker.NewStore()
+	files := entityTracker.NewFileAggregator()
+
+	file, _ := files.Track(fileOpen(100, 42, "/etc/passwd", state.FILE_OPEN_TO_READ))
+	if record, err := store.Update(file, baseTime, "01JXB4T5K3Z8Q2W9E7R6Y5V4N3"); err != nil || record != nil {
+		t.Fatalf("New entity has a change record: %+v, %v", record, err)
+	}
+
+	file, _ = files.Track(fileOpen(200, 42, "/etc/passwd", state.FILE_OPEN_TO_READ))
+	record, err := store.Update(file, baseTime.Add(time.Second), "01JXB4T5K3Z8Q2W9E7R6Y5V4N4")
+	if err != nil {
+		t.Fatalf("Failed to update entity: %v", err)
+	}
+	fields := []string{}
+	for _, change := range record.Changes {
+		fields = append(fields, change.Field)
+	}
+	want := []string{"State", "NumReadOps", "LastOpenerPID"}
+	if !slices.Equal(fields, want) || record.EventID != "01JXB4T5K3Z8Q2W9E7R6Y5V4N4" {
+		t.Fatalf("Change record does not match: got %v, want %v", fields, want)
+	}
+}